@@ -2,7 +2,13 @@ package main
 
 import (
 	"fmt"
+	"log/slog"
+	"net/url"
 	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/Veraticus/claude-code-ntfy/pkg/config"
@@ -19,6 +25,14 @@ type Dependencies struct {
 	OutputMonitor  interfaces.DataHandler
 	ProcessManager *process.Manager
 	stopChan       chan struct{}
+
+	startupCoalescer  *notification.StartupCoalescer
+	heartbeatNotifier notification.Notifier
+	exitNotifier      notification.Notifier
+	errorReporter     *notification.ErrorReporter
+	digest            *notification.DigestNotifier
+	stats             *notification.SessionStats
+	failures          *notification.FailureCounter
 }
 
 // NewDependencies creates all dependencies with the given configuration
@@ -28,35 +42,299 @@ func NewDependencies(cfg *config.Config) (*Dependencies, error) {
 		stopChan: make(chan struct{}),
 	}
 
-	// Create notification components
-	baseNotifier := notification.NewNtfyClient(cfg.NtfyServer, cfg.NtfyTopic)
-
 	// Create output monitor with stdout notifier temporarily
 	outputMonitor := monitor.NewOutputMonitor(cfg, notification.NewStdoutNotifier())
 
-	// Wrap with context notifier
-	contextNotifier := notification.NewContextNotifier(baseNotifier, func() string {
-		return outputMonitor.GetTerminalTitle()
-	})
+	titleContext, err := newNotifierChain(cfg, deps, outputMonitor.GetTerminalTitle)
+	if err != nil {
+		return nil, err
+	}
+	var contextNotifier notification.Notifier = titleContext
+
+	// Drop notifications while `claude-code-ntfy snooze` is active
+	if snoozePath := config.SnoozePath(); snoozePath != "" {
+		contextNotifier = notification.NewSnoozeNotifier(contextNotifier, snoozePath)
+	}
+
+	// Skip notifications while Claude's tmux pane is in front of the user
+	if pane := os.Getenv("TMUX_PANE"); cfg.TmuxPaneAware && pane != "" {
+		contextNotifier = notification.NewTmuxPaneNotifier(contextNotifier, pane)
+	}
+
+	// Skip notifications while the terminal is the frontmost app on macOS
+	if cfg.RespectFrontmostApp && runtime.GOOS == "darwin" {
+		contextNotifier = notification.NewFrontmostAppNotifier(contextNotifier)
+	}
+
+	// Stay silent until the first prompt is submitted
+	var inputHandler func([]byte)
+	if cfg.SuppressUntilPrompt {
+		promptGate := notification.NewPromptGate(contextNotifier)
+		inputHandler = promptGate.HandleInput
+		contextNotifier = promptGate
+	}
+
+	// Count every notification raised for the exit summary
+	if deps.stats != nil {
+		contextNotifier = deps.stats.CountTriggered(contextNotifier)
+	}
+
+	// Hold the startup notification back so an early notification can replace it.
+	// Only Claude's output replaces it, not our own timers and alerts.
+	if cfg.StartupCoalesceWindow > 0 {
+		deps.startupCoalescer = notification.NewStartupCoalescer(contextNotifier, cfg.StartupCoalesceWindow,
+			"heartbeat", "backstop", "first_output", "hang", "max_runtime", "exit")
+		contextNotifier = deps.startupCoalescer
+	}
+
+	// Heartbeats bypass the backstop so they don't count as Claude activity
+	if cfg.HeartbeatInterval > 0 {
+		deps.heartbeatNotifier = contextNotifier
+	}
+
+	// Neither does Claude exiting, and the backstop has nothing left to watch
+	if cfg.NotifyOnExit {
+		deps.exitNotifier = contextNotifier
+	}
 
 	// Wrap with backstop notifier if configured
 	var finalNotifier notification.Notifier = contextNotifier
 	if cfg.BackstopTimeout > 0 {
-		finalNotifier = notification.NewBackstopNotifier(contextNotifier, cfg.BackstopTimeout)
+		backstop := notification.NewBackstopNotifier(contextNotifier, cfg.BackstopTimeout)
+		backstop.SetTimeoutDecay(cfg.BackstopMinTimeout, cfg.BackstopDecay)
+		finalNotifier = backstop
 	}
 	deps.Notifier = finalNotifier
 
-	// Update the output monitor with the final notifier
+	// Update the output monitor with the final notifier; its own alerts
+	// bypass the backstop like heartbeats, so they don't count as activity
 	outputMonitor.SetNotifier(deps.Notifier)
+	outputMonitor.SetAlertNotifier(contextNotifier)
 	deps.OutputMonitor = outputMonitor
 
-	// Create process manager without input handler
+	// Create process manager; input is only watched for the prompt gate
 	// The backstop timer will only be reset when visible output is detected
-	deps.ProcessManager = process.NewManager(cfg, deps.OutputMonitor, nil)
+	deps.ProcessManager = process.NewManager(cfg, deps.OutputMonitor, inputHandler)
+	if deps.errorReporter != nil {
+		deps.ProcessManager.SetErrorHandler(deps.errorReporter.Report)
+	}
 
 	return deps, nil
 }
 
+// newNotifierChain builds the notifiers every notification goes through, from the
+// backends up to the title context. With nil deps it builds the chain for a one-off
+// send, without the parts that only make sense over a session: error reports,
+// failure counting, stats, the nested-session discard and the digest.
+func newNotifierChain(cfg *config.Config, deps *Dependencies, terminalTitle func() string) (*notification.ContextNotifier, error) {
+	// Create notification components
+	baseNotifier, err := newBackends(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// A nested session passes Claude straight through and never notifies
+	session := deps != nil
+	nested := session && cfg.AllowNested && process.IsNested()
+
+	// Report internal errors, straight to the backends so a report can't be vetoed or delayed
+	if session && cfg.NotifyInternalErrors && !nested {
+		deps.errorReporter = notification.NewErrorReporter(baseNotifier)
+		// A lone backend's failures would only be reported back to the backend that failed
+		if hasSeveralTargets(baseNotifier) {
+			baseNotifier = deps.errorReporter.ReportFailures(baseNotifier)
+		}
+	}
+
+	// Remember failed sends so they can fail the run
+	if session && cfg.FailOnNotifyError {
+		deps.failures = notification.NewFailureCounter(baseNotifier)
+		baseNotifier = deps.failures
+	}
+
+	// Count what reaches ntfy for the exit summary
+	if session && cfg.StatsOnExit {
+		deps.stats = notification.NewSessionStats()
+		baseNotifier = deps.stats.CountDelivered(baseNotifier)
+	}
+
+	// Run the post-notification hook after each send
+	if cfg.PostHook != "" {
+		baseNotifier = notification.NewPostHookNotifier(baseNotifier, cfg.PostHook)
+	}
+
+	// Tag notifications with this run's id so they can be correlated
+	if cfg.IncludeSessionID {
+		sessionID := notification.NewSessionID()
+		// Tag our log lines too, so they can be matched with the notifications
+		slog.SetDefault(slog.Default().With("session_id", sessionID))
+		baseNotifier = notification.NewSessionIDNotifier(baseNotifier, sessionID)
+	}
+
+	// Number notifications so lost ones show up as gaps
+	if cfg.IncludeSequence {
+		baseNotifier = notification.NewSequenceNotifier(baseNotifier)
+	}
+
+	// Let the pre-notification hook modify or veto each notification
+	if cfg.PreHook != "" {
+		baseNotifier = notification.NewPreHookNotifier(baseNotifier, cfg.PreHook)
+	}
+
+	// Drop everything in a nested session
+	if nested {
+		baseNotifier = notification.NewDiscardNotifier()
+	}
+
+	// Add the configured message prefix/suffix
+	if cfg.MessagePrefix != "" || cfg.MessageSuffix != "" {
+		baseNotifier = notification.NewAffixNotifier(baseNotifier, cfg.MessagePrefix, cfg.MessageSuffix)
+	}
+
+	// Open the click_url when a notification is tapped
+	if cfg.ClickURL != "" {
+		clickTemplate, err := notification.ParseClickTemplate(cfg.ClickURL)
+		if err != nil {
+			return nil, err
+		}
+		baseNotifier = notification.NewClickNotifier(baseNotifier, clickTemplate, terminalTitle)
+	}
+
+	// Send one summary per digest_interval instead of every notification. Heartbeats
+	// and the exit notification still go out on time, or they would tell nothing.
+	if session && cfg.DigestInterval > 0 {
+		deps.digest = notification.NewDigestNotifier(baseNotifier, "heartbeat", "exit")
+		baseNotifier = deps.digest
+		if deps.stats != nil {
+			baseNotifier = deps.stats.CountDigested(deps.digest)
+		}
+	}
+
+	// Wrap with context notifier
+	titleContext := notification.NewContextNotifier(baseNotifier, terminalTitle)
+	titleContext.SetMessageFromTitle(cfg.MessageFromTitle)
+	titleContext.SetMaxTitleLength(cfg.MaxTerminalTitleLength)
+	if cfg.IncludeGitContext {
+		titleContext.UseGitContext()
+	}
+
+	return titleContext, nil
+}
+
+// newBackends creates the notifier for every configured backend, plus the fallback
+func newBackends(cfg *config.Config) (notification.Notifier, error) {
+	names := cfg.Backends
+	if len(names) == 0 {
+		names = []string{"ntfy"}
+	}
+
+	backends := make([]notification.Notifier, 0, len(names))
+	for _, name := range names {
+		backend, err := newBackend(cfg, name)
+		if err != nil && name == "desktop" {
+			// Keep running with the other backends rather than failing the whole session
+			slog.Warn("skipping desktop backend", "err", err)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		backends = append(backends, backend)
+	}
+
+	if len(backends) == 0 {
+		return nil, fmt.Errorf("no notification backend available")
+	}
+
+	var notifier notification.Notifier = notification.NewMultiNotifier(backends...)
+	if len(backends) == 1 {
+		notifier = backends[0]
+	}
+
+	if cfg.FallbackBackend != "" {
+		fallback, err := newBackend(cfg, cfg.FallbackBackend)
+		if err != nil && cfg.FallbackBackend == "desktop" {
+			slog.Warn("skipping desktop fallback", "err", err)
+			return notifier, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		notifier = notification.NewFallbackNotifier(notifier, fallback)
+	}
+
+	return notifier, nil
+}
+
+// hasSeveralTargets reports whether notifier, as built by newBackends, sends to more than one backend
+func hasSeveralTargets(notifier notification.Notifier) bool {
+	switch notifier.(type) {
+	case *notification.MultiNotifier, *notification.FallbackNotifier:
+		return true
+	default:
+		return false
+	}
+}
+
+// newBackend creates the notifier for a single backend
+func newBackend(cfg *config.Config, name string) (notification.Notifier, error) {
+	switch name {
+	case "ntfy":
+		return newNtfyClient(cfg)
+	case "stdout":
+		return notification.NewStdoutNotifier(), nil
+	case "webhook":
+		return notification.NewWebhookNotifier(cfg.WebhookURL, cfg.WebhookMethod, cfg.WebhookHeaders, cfg.WebhookBodyTemplate)
+	case "desktop":
+		return notification.NewDesktopNotifier(notification.DesktopOptions{
+			MacOSNotifier: cfg.MacOSNotifier,
+			Retries:       cfg.DesktopRetries,
+			FocusTerminal: cfg.ClickFocusTerminal,
+			TmuxPane:      os.Getenv("TMUX_PANE"),
+		})
+	default:
+		return nil, fmt.Errorf("unknown notification backend %q", name)
+	}
+}
+
+// newNtfyClient creates the ntfy client with the configured connection and delivery options
+func newNtfyClient(cfg *config.Config) (*notification.NtfyClient, error) {
+	ntfyOpts, err := ntfyConnectionOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.RetryAttempts > 0 {
+		ntfyOpts = append(ntfyOpts, notification.WithRetry(cfg.RetryAttempts, cfg.RetryBaseDelay))
+	}
+	if cfg.Markdown != "" {
+		ntfyOpts = append(ntfyOpts, notification.WithMarkdown(notification.MarkdownMode(cfg.Markdown)))
+	}
+	ntfyOpts = append(ntfyOpts, notification.WithDeliveryLog(slog.Default()))
+	return notification.NewNtfyClient(cfg.NtfyServer, cfg.NtfyTopic, ntfyOpts...), nil
+}
+
+// ntfyConnectionOptions returns the options for reaching the ntfy server: timeout, auth and proxy
+func ntfyConnectionOptions(cfg *config.Config) ([]notification.NtfyOption, error) {
+	var ntfyOpts []notification.NtfyOption
+	if cfg.NtfyTimeout > 0 {
+		ntfyOpts = append(ntfyOpts, notification.WithTimeout(cfg.NtfyTimeout))
+	}
+	if cfg.NtfyDisableKeepAlive {
+		ntfyOpts = append(ntfyOpts, notification.WithDisableKeepAlives())
+	}
+	if cfg.NtfyUsername != "" && cfg.NtfyPassword != "" {
+		ntfyOpts = append(ntfyOpts, notification.WithBasicAuth(cfg.NtfyUsername, cfg.NtfyPassword))
+	}
+	if cfg.NtfyProxy != "" {
+		proxyURL, err := url.Parse(cfg.NtfyProxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ntfy_proxy: %w", err)
+		}
+		ntfyOpts = append(ntfyOpts, notification.WithProxy(proxyURL))
+	}
+	return ntfyOpts, nil
+}
+
 // Close cleans up all dependencies
 func (d *Dependencies) Close() {
 	// Stop status indicator refresh
@@ -70,11 +348,19 @@ func (d *Dependencies) Close() {
 		d.stopChan = nil
 	}
 
+	// Stop output monitor timers
+	if outputMonitor, ok := d.OutputMonitor.(*monitor.OutputMonitor); ok {
+		outputMonitor.Close()
+	}
+
 	// Close notifiers
 	// First try to close as backstop notifier
 	if backstopNotifier, ok := d.Notifier.(*notification.BackstopNotifier); ok {
 		_ = backstopNotifier.Close()
 	}
+	if d.startupCoalescer != nil {
+		_ = d.startupCoalescer.Close()
+	}
 }
 
 // Application represents the main application
@@ -93,21 +379,213 @@ func NewApplication(deps *Dependencies) *Application {
 func (a *Application) Run(command string, args []string) error {
 	// Send startup notification if configured
 	if a.deps.Config.StartupNotify && !a.deps.Config.Quiet {
-		pwd, _ := os.Getwd()
-		startupNotification := notification.Notification{
-			Title:   "Claude Code Session Started",
-			Message: fmt.Sprintf("Working directory: %s", pwd),
-			Time:    time.Now(),
-			Pattern: "startup",
+		startupNotification := newStartupNotification(a.deps.Config, command, args)
+		if a.deps.startupCoalescer != nil {
+			a.deps.startupCoalescer.SendStartup(startupNotification)
+		} else {
+			_ = a.deps.Notifier.Send(startupNotification)
 		}
-		_ = a.deps.Notifier.Send(startupNotification)
 	}
 
 	if err := a.deps.ProcessManager.Start(command, args); err != nil {
 		return err
 	}
 
-	return a.deps.ProcessManager.Wait()
+	// Stop Claude once it has run for the configured maximum
+	if a.deps.Config.MaxRuntime > 0 {
+		timer := time.AfterFunc(a.deps.Config.MaxRuntime, a.stopForMaxRuntime)
+		defer timer.Stop()
+	}
+
+	// Send periodic heartbeats until Claude exits
+	if a.deps.heartbeatNotifier != nil && !a.deps.Config.Quiet {
+		ticker := time.NewTicker(a.deps.Config.HeartbeatInterval)
+		stop := make(chan struct{})
+		defer func() {
+			ticker.Stop()
+			close(stop)
+		}()
+		go runHeartbeat(a.deps.heartbeatNotifier, ticker.C, stop, time.Now())
+	}
+
+	// Send a digest every interval until Claude exits
+	if a.deps.digest != nil {
+		ticker := time.NewTicker(a.deps.Config.DigestInterval)
+		stop := make(chan struct{})
+		defer func() {
+			ticker.Stop()
+			close(stop)
+		}()
+		go a.deps.digest.Run(ticker.C, stop)
+	}
+
+	err := a.deps.ProcessManager.Wait()
+
+	if a.deps.exitNotifier != nil && !a.deps.Config.Quiet {
+		var stats string
+		if a.deps.Config.ExitStats {
+			stats = a.deps.ProcessManager.ExitStats().String()
+		}
+		_ = a.deps.exitNotifier.Send(newExitNotification(a.deps.ProcessManager.ExitCode(), stats))
+	}
+
+	// Don't lose what was held back since the last digest
+	if a.deps.digest != nil {
+		_ = a.deps.digest.Flush(time.Now())
+	}
+
+	if a.deps.stats != nil {
+		_ = a.deps.stats.WriteSummary(os.Stderr)
+	}
+
+	if a.deps.Config.ExitStats {
+		fmt.Fprintf(os.Stderr, "claude-code-ntfy: claude %s\n", a.deps.ProcessManager.ExitStats())
+	}
+
+	if a.deps.failures != nil && a.deps.failures.Failures() > 0 {
+		fmt.Fprintf(os.Stderr, "claude-code-ntfy: %d notification(s) failed to send\n", a.deps.failures.Failures())
+	}
+
+	return err
+}
+
+// heartbeatPriority keeps heartbeats from buzzing the phone
+const heartbeatPriority = 2
+
+// maxRuntimeGrace is how long Claude gets to exit after SIGTERM before it is killed
+const maxRuntimeGrace = 10 * time.Second
+
+// stopForMaxRuntime notifies that the maximum runtime was reached and stops Claude
+func (a *Application) stopForMaxRuntime() {
+	if !a.deps.Config.Quiet {
+		_ = a.deps.Notifier.Send(notification.Notification{
+			Title:   "Claude stopped",
+			Message: fmt.Sprintf("Max runtime of %s reached", a.deps.Config.MaxRuntime),
+			Time:    time.Now(),
+			Pattern: "max_runtime",
+		})
+	}
+
+	if err := a.deps.ProcessManager.StopWithGrace(maxRuntimeGrace); err != nil {
+		slog.Error("failed to stop claude after max runtime", "err", err)
+		if a.deps.errorReporter != nil {
+			a.deps.errorReporter.Report(fmt.Errorf("failed to stop claude after max runtime: %w", err))
+		}
+	}
+}
+
+// exitErrorPriority makes a crash stand out from a normal exit
+const exitErrorPriority = 4
+
+// newExitNotification builds the notification sent when Claude exits with exitCode,
+// which is -1 if it was killed by a signal. stats, if set, is added to the message.
+func newExitNotification(exitCode int, stats string) notification.Notification {
+	n := notification.Notification{
+		Title:   "Claude exited",
+		Message: "Claude exited (code 0)",
+		Time:    time.Now(),
+		Pattern: "exit",
+	}
+
+	switch {
+	case exitCode < 0:
+		n.Title = "Claude was killed"
+		n.Message = "Claude was terminated by a signal"
+	case exitCode > 0:
+		n.Title = "Claude failed"
+		n.Message = fmt.Sprintf("Claude exited (code %d)", exitCode)
+	}
+	if exitCode != 0 {
+		n.Priority = exitErrorPriority
+		n.Tags = []string{"warning"}
+	}
+
+	if stats != "" {
+		n.Message += ", " + stats
+	}
+	return n
+}
+
+// newStartupNotification builds the notification sent when the session starts
+func newStartupNotification(cfg *config.Config, command string, args []string) notification.Notification {
+	pwd, _ := os.Getwd()
+	message := fmt.Sprintf("Working directory: %s", pwd)
+	if cfg.StartupIncludeCommand {
+		commandLine := append([]string{filepath.Base(command)}, redactArgs(args)...)
+		message += fmt.Sprintf("\nCommand: %s", strings.Join(commandLine, " "))
+	}
+
+	return notification.Notification{
+		Title:   "Claude Code Session Started",
+		Message: message,
+		Time:    time.Now(),
+		Pattern: "startup",
+	}
+}
+
+// sensitiveFlagWords mark flags whose values must not be sent in notifications
+var sensitiveFlagWords = []string{"key", "token", "secret", "password", "auth"}
+
+// redactArgs returns args with the values of sensitive flags replaced,
+// for both "--api-key VALUE" and "--api-key=VALUE"
+func redactArgs(args []string) []string {
+	redacted := make([]string, len(args))
+	redactNext := false
+	for i, arg := range args {
+		if redactNext && !strings.HasPrefix(arg, "-") {
+			redacted[i] = "[REDACTED]"
+			redactNext = false
+			continue
+		}
+		redactNext = false
+		redacted[i] = arg
+
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name, _, hasValue := strings.Cut(arg, "=")
+		if !isSensitiveFlag(name) {
+			continue
+		}
+		if hasValue {
+			redacted[i] = name + "=[REDACTED]"
+		} else {
+			redactNext = true
+		}
+	}
+	return redacted
+}
+
+// isSensitiveFlag reports whether a flag name looks like it takes a credential.
+// Whole words are compared so that e.g. --max-tokens is left alone.
+func isSensitiveFlag(name string) bool {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r == '-' || r == '_'
+	})
+	for _, word := range words {
+		if slices.Contains(sensitiveFlagWords, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// runHeartbeat sends a low priority heartbeat notification on every tick until stop is closed
+func runHeartbeat(notifier notification.Notifier, ticks <-chan time.Time, stop <-chan struct{}, started time.Time) {
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticks:
+			_ = notifier.Send(notification.Notification{
+				Title:    "Claude Code is still running",
+				Message:  fmt.Sprintf("Running for %s", now.Sub(started).Round(time.Minute)),
+				Time:     now,
+				Pattern:  "heartbeat",
+				Priority: heartbeatPriority,
+			})
+		}
+	}
 }
 
 // Stop gracefully stops the application
@@ -115,7 +593,12 @@ func (a *Application) Stop() error {
 	return a.deps.ProcessManager.Stop()
 }
 
-// ExitCode returns the exit code of the wrapped process
+// ExitCode returns the exit code of the wrapped process.
+// With fail_on_notify_error, a successful run still exits 1 if any notification failed to send.
 func (a *Application) ExitCode() int {
-	return a.deps.ProcessManager.ExitCode()
+	code := a.deps.ProcessManager.ExitCode()
+	if code == 0 && a.deps.failures != nil && a.deps.failures.Failures() > 0 {
+		return 1
+	}
+	return code
 }
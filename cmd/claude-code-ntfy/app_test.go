@@ -1,11 +1,20 @@
 package main
 
 import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/Veraticus/claude-code-ntfy/pkg/config"
+	"github.com/Veraticus/claude-code-ntfy/pkg/notification"
+	"github.com/Veraticus/claude-code-ntfy/pkg/testutil"
 )
 
 func TestNewDependencies(t *testing.T) {
@@ -87,6 +96,347 @@ func TestApplication_Run(t *testing.T) {
 	}
 }
 
+func TestNewDependencies_NestedDiscardsNotifications(t *testing.T) {
+	t.Setenv("CLAUDE_CODE_NTFY_WRAPPED", "1")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("nested session should not reach ntfy")
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		NtfyTopic:   "test-topic",
+		NtfyServer:  server.URL,
+		AllowNested: true,
+	}
+
+	deps, err := NewDependencies(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer deps.Close()
+
+	if err := deps.Notifier.Send(notification.Notification{Title: "Test"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestNewBackends(t *testing.T) {
+	tests := []struct {
+		name     string
+		backends []string
+		fallback string
+		wantType notification.Notifier
+		wantErr  bool
+	}{
+		{name: "default is ntfy", wantType: &notification.NtfyClient{}},
+		{name: "single backend", backends: []string{"stdout"}, wantType: &notification.StdoutNotifier{}},
+		{name: "several backends", backends: []string{"ntfy", "stdout"}, wantType: &notification.MultiNotifier{}},
+		{name: "unknown backend", backends: []string{"pigeon"}, wantErr: true},
+		{name: "with fallback", backends: []string{"ntfy"}, fallback: "stdout", wantType: &notification.FallbackNotifier{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{NtfyTopic: "test-topic", Backends: tt.backends, FallbackBackend: tt.fallback}
+
+			notifier, err := newBackends(cfg)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if fmt.Sprintf("%T", notifier) != fmt.Sprintf("%T", tt.wantType) {
+				t.Errorf("expected %T, got %T", tt.wantType, notifier)
+			}
+		})
+	}
+}
+
+func TestApplication_ExitCodeOnNotifyError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name              string
+		failOnNotifyError bool
+		wantCode          int
+	}{
+		{"best effort by default", false, 0},
+		{"fail on notify error", true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				NtfyTopic:         "test-topic",
+				NtfyServer:        server.URL,
+				FailOnNotifyError: tt.failOnNotifyError,
+			}
+
+			deps, err := NewDependencies(cfg)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer deps.Close()
+
+			app := NewApplication(deps)
+			if code := app.ExitCode(); code != 0 {
+				t.Errorf("expected exit code 0 before any failure, got %d", code)
+			}
+
+			if err := deps.Notifier.Send(notification.Notification{Title: "Test", Pattern: "backstop"}); err == nil {
+				t.Fatal("expected send to fail")
+			}
+
+			if code := app.ExitCode(); code != tt.wantCode {
+				t.Errorf("expected exit code %d, got %d", tt.wantCode, code)
+			}
+		})
+	}
+}
+
+func TestNewExitNotification(t *testing.T) {
+	tests := []struct {
+		name         string
+		exitCode     int
+		stats        string
+		wantTitle    string
+		wantMessage  string
+		wantPriority int
+	}{
+		{
+			name:        "normal exit",
+			exitCode:    0,
+			wantTitle:   "Claude exited",
+			wantMessage: "Claude exited (code 0)",
+		},
+		{
+			name:         "non-zero exit",
+			exitCode:     2,
+			wantTitle:    "Claude failed",
+			wantMessage:  "Claude exited (code 2)",
+			wantPriority: exitErrorPriority,
+		},
+		{
+			name:         "killed by a signal",
+			exitCode:     -1,
+			wantTitle:    "Claude was killed",
+			wantMessage:  "Claude was terminated by a signal",
+			wantPriority: exitErrorPriority,
+		},
+		{
+			name:        "with exit stats",
+			exitCode:    0,
+			stats:       "ran 1h2m3s, CPU 12.35s user, 1.2s system",
+			wantTitle:   "Claude exited",
+			wantMessage: "Claude exited (code 0), ran 1h2m3s, CPU 12.35s user, 1.2s system",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := newExitNotification(tt.exitCode, tt.stats)
+
+			if n.Title != tt.wantTitle || n.Message != tt.wantMessage {
+				t.Errorf("expected %q: %q, got %q: %q", tt.wantTitle, tt.wantMessage, n.Title, n.Message)
+			}
+			if n.Priority != tt.wantPriority {
+				t.Errorf("expected priority %d, got %d", tt.wantPriority, n.Priority)
+			}
+			if n.Pattern != "exit" {
+				t.Errorf("expected pattern exit, got %q", n.Pattern)
+			}
+		})
+	}
+}
+
+func TestApplication_NotifyOnExit(t *testing.T) {
+	cfg := &config.Config{
+		NtfyTopic:    "test-topic",
+		NotifyOnExit: true,
+	}
+
+	deps, err := NewDependencies(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer deps.Close()
+
+	mockNotifier := testutil.NewMockNotifier()
+	deps.exitNotifier = mockNotifier
+
+	if err := NewApplication(deps).Run("false", nil); err == nil {
+		t.Fatal("expected the exit error of false")
+	}
+
+	notifications := mockNotifier.GetNotifications()
+	if len(notifications) != 1 || notifications[0].Message != "Claude exited (code 1)" {
+		t.Fatalf("expected one exit notification with code 1, got %v", notifications)
+	}
+}
+
+func TestNewStartupNotification(t *testing.T) {
+	args := []string{"--model", "opus", "--api-key", "sk-secret", "--auth-token=abc123", "--max-tokens", "100", "fix the tests"}
+
+	tests := []struct {
+		name      string
+		include   bool
+		wantIn    []string
+		wantNotIn []string
+	}{
+		{
+			name:      "command left out by default",
+			include:   false,
+			wantIn:    []string{"Working directory: "},
+			wantNotIn: []string{"Command:", "opus"},
+		},
+		{
+			name:    "command included with secrets redacted",
+			include: true,
+			wantIn: []string{
+				"Command: claude --model opus --api-key [REDACTED] --auth-token=[REDACTED] --max-tokens 100 fix the tests",
+			},
+			wantNotIn: []string{"sk-secret", "abc123"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{StartupIncludeCommand: tt.include}
+			n := newStartupNotification(cfg, "/usr/local/bin/claude", args)
+
+			if n.Pattern != "startup" {
+				t.Errorf("expected pattern startup, got %q", n.Pattern)
+			}
+			for _, want := range tt.wantIn {
+				if !strings.Contains(n.Message, want) {
+					t.Errorf("expected message to contain %q, got %q", want, n.Message)
+				}
+			}
+			for _, unwanted := range tt.wantNotIn {
+				if strings.Contains(n.Message, unwanted) {
+					t.Errorf("expected message not to contain %q, got %q", unwanted, n.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestApplication_MaxRuntime(t *testing.T) {
+	cfg := &config.Config{
+		NtfyTopic:  "test-topic",
+		MaxRuntime: 200 * time.Millisecond,
+	}
+
+	deps, err := NewDependencies(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer deps.Close()
+
+	mockNotifier := testutil.NewMockNotifier()
+	deps.Notifier = mockNotifier
+
+	app := NewApplication(deps)
+
+	done := make(chan error, 1)
+	start := time.Now()
+	go func() { done <- app.Run("sleep", []string{"30"}) }()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		_ = app.Stop()
+		t.Fatal("expected claude to be stopped after max runtime")
+	}
+
+	if elapsed := time.Since(start); elapsed < cfg.MaxRuntime {
+		t.Errorf("stopped after %v, before max runtime", elapsed)
+	}
+
+	notifications := mockNotifier.GetNotifications()
+	if len(notifications) != 1 || notifications[0].Pattern != "max_runtime" {
+		t.Fatalf("expected one max_runtime notification, got %v", notifications)
+	}
+}
+
+func TestApplication_DigestFlushedOnExit(t *testing.T) {
+	cfg := &config.Config{
+		NtfyTopic:      "test-topic",
+		DigestInterval: time.Hour,
+	}
+
+	deps, err := NewDependencies(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer deps.Close()
+
+	mockNotifier := testutil.NewMockNotifier()
+	deps.digest = notification.NewDigestNotifier(mockNotifier)
+	_ = deps.digest.Send(notification.Notification{Message: "Claude needs your input", Pattern: "bell"})
+
+	if err := NewApplication(deps).Run("true", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	notifications := mockNotifier.GetNotifications()
+	if len(notifications) != 1 || notifications[0].Pattern != "digest" {
+		t.Fatalf("expected the held back notification in a digest at exit, got %v", notifications)
+	}
+}
+
+func TestRunHeartbeat(t *testing.T) {
+	mock := testutil.NewMockNotifier()
+	ticks := make(chan time.Time)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	started := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	go func() {
+		runHeartbeat(mock, ticks, stop, started)
+		close(done)
+	}()
+
+	ticks <- started.Add(30 * time.Minute)
+	ticks <- started.Add(60 * time.Minute)
+
+	close(stop)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("heartbeat did not stop")
+	}
+
+	notifications := mock.GetNotifications()
+	if len(notifications) != 2 {
+		t.Fatalf("expected 2 heartbeats, got %d", len(notifications))
+	}
+	if notifications[0].Pattern != "heartbeat" {
+		t.Errorf("Pattern = %q, want heartbeat", notifications[0].Pattern)
+	}
+	if notifications[0].Priority != heartbeatPriority {
+		t.Errorf("Priority = %d, want %d", notifications[0].Priority, heartbeatPriority)
+	}
+	if notifications[1].Message != "Running for 1h0m0s" {
+		t.Errorf("Message = %q, want Running for 1h0m0s", notifications[1].Message)
+	}
+
+	// No further heartbeats once stopped
+	select {
+	case ticks <- started.Add(90 * time.Minute):
+		t.Error("heartbeat still receiving ticks after stop")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
 func TestIsatty(t *testing.T) {
 	// Test isatty function
 	// stderr is typically not a tty in test environment
@@ -94,3 +444,89 @@ func TestIsatty(t *testing.T) {
 		t.Skip("stderr is a tty in test environment")
 	}
 }
+
+func TestNewDependencies_NestedSkipsErrorReporter(t *testing.T) {
+	t.Setenv("CLAUDE_CODE_NTFY_WRAPPED", "1")
+
+	cfg := &config.Config{
+		NtfyTopic:            "test-topic",
+		AllowNested:          true,
+		NotifyInternalErrors: true,
+	}
+
+	deps, err := NewDependencies(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer deps.Close()
+
+	if deps.errorReporter != nil {
+		t.Error("expected no error reporter in a nested session")
+	}
+}
+
+func TestNewDependencies_ErrorReporterTargets(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		backends     []string
+		wantRequests int32
+	}{
+		// The failure isn't reported back to the ntfy server that failed
+		{"single backend", []string{"ntfy"}, 1},
+		// The failure is reported to every backend, ntfy included
+		{"several backends", []string{"ntfy", "stdout"}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests.Store(0)
+			cfg := &config.Config{
+				NtfyTopic:            "test-topic",
+				NtfyServer:           server.URL,
+				Backends:             tt.backends,
+				NotifyInternalErrors: true,
+			}
+
+			deps, err := NewDependencies(cfg)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer deps.Close()
+
+			_ = deps.Notifier.Send(notification.Notification{Title: "Test"})
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("expected %d requests to ntfy, got %d", tt.wantRequests, got)
+			}
+		})
+	}
+}
+
+func TestNewDependencies_SessionIDOnLogLines(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+
+	var logs bytes.Buffer
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+
+	cfg := &config.Config{
+		NtfyTopic:        "test-topic",
+		IncludeSessionID: true,
+	}
+
+	deps, err := NewDependencies(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer deps.Close()
+
+	slog.Info("some event")
+	if !strings.Contains(logs.String(), "session_id=") {
+		t.Errorf("expected the session id on the log line, got %q", logs.String())
+	}
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Veraticus/claude-code-ntfy/pkg/config"
+	"github.com/Veraticus/claude-code-ntfy/pkg/notification"
+	flag "github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// runCommand runs one of our own subcommands.
+// It returns false if name is not a subcommand, so the arguments go to Claude.
+func runCommand(name string, args []string) (int, bool) {
+	switch name {
+	case "subscribe":
+		return runSubscribe(args), true
+	case "snooze":
+		return runSnooze(args), true
+	case "test":
+		return runTestNotification(args), true
+	case "validate":
+		return runValidate(args), true
+	default:
+		return 0, false
+	}
+}
+
+// loadCommandConfig parses a subcommand's flags and loads the configuration.
+// --config is applied before loading so it takes effect for subcommands.
+func loadCommandConfig(name string, args []string) (*config.Config, error) {
+	var configPath string
+
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	flags.StringVar(&configPath, "config", "", "Path to config file")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if configPath != "" {
+		if err := os.Setenv("CLAUDE_NOTIFY_CONFIG", configPath); err != nil {
+			return nil, fmt.Errorf("setting config path: %w", err)
+		}
+	}
+
+	return config.Load()
+}
+
+// runSubscribe streams notifications from the configured ntfy topic to stdout
+func runSubscribe(args []string) int {
+	cfg, err := loadCommandConfig("subscribe", args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+
+	ntfyOpts, err := ntfyConnectionOptions(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(os.Stderr, "claude-code-ntfy: subscribed to %s, press Ctrl-C to stop\n", cfg.NtfyServer)
+
+	subscriber := notification.NewNtfySubscriber(cfg.NtfyServer, cfg.NtfyTopic, ntfyOpts...)
+	if err := subscriber.Subscribe(ctx, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error subscribing: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+// runTestNotification sends one notification through the same chain a session uses,
+// so hooks, prefixes and click URLs are exercised, but skipping snooze, focus and
+// the other session gates, to check the setup end to end
+func runTestNotification(args []string) int {
+	cfg, err := loadCommandConfig("test", args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+
+	// There is no terminal title outside a session
+	notifier, err := newNotifierChain(cfg, nil, func() string { return "" })
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	err = notifier.Send(notification.Notification{
+		Title:   "Claude Code: test notification",
+		Message: "claude-code-ntfy is set up correctly",
+		Time:    time.Now(),
+		Pattern: "test",
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error sending test notification: %v\n", err)
+		return 1
+	}
+
+	fmt.Println("Test notification sent")
+	return 0
+}
+
+// runValidate loads and validates the configuration without contacting any server,
+// then prints the resolved settings
+func runValidate(args []string) int {
+	cfg, err := loadCommandConfig("validate", args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	path := config.Path()
+	if _, err := os.Stat(path); err != nil {
+		fmt.Printf("Config file: %s (not found, using defaults and environment)\n", path)
+	} else {
+		fmt.Printf("Config file: %s\n", path)
+	}
+	fmt.Println("Configuration is valid. Resolved settings:")
+
+	if err := writeSettings(os.Stdout, cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// secretMask replaces credentials in the printed settings
+const secretMask = "********"
+
+// writeSettings writes the configuration as YAML with credentials masked
+func writeSettings(w io.Writer, cfg *config.Config) error {
+	masked := *cfg
+	if masked.NtfyPassword != "" {
+		masked.NtfyPassword = secretMask
+	}
+	masked.WebhookURL = redactURL(cfg.WebhookURL)
+	masked.NtfyProxy = redactURL(cfg.NtfyProxy)
+	if len(masked.WebhookHeaders) > 0 {
+		// Header values are often tokens
+		masked.WebhookHeaders = make(map[string]string, len(cfg.WebhookHeaders))
+		for name := range cfg.WebhookHeaders {
+			masked.WebhookHeaders[name] = secretMask
+		}
+	}
+
+	data, err := yaml.Marshal(&masked)
+	if err != nil {
+		return fmt.Errorf("failed to format settings: %w", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// redactURL masks the parts of a URL that can carry credentials: the userinfo,
+// the path (webhook services often put the token there) and the query values.
+// The scheme and host are kept so the output still shows where requests go.
+func redactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return secretMask
+	}
+
+	redacted := u.Scheme + "://"
+	if u.User != nil {
+		redacted += secretMask + "@"
+	}
+	redacted += u.Host
+	if u.Path != "" && u.Path != "/" {
+		redacted += "/" + secretMask
+	}
+
+	if u.RawQuery != "" {
+		query := u.Query()
+		params := make([]string, 0, len(query))
+		for key := range query {
+			params = append(params, url.QueryEscape(key)+"="+secretMask)
+		}
+		// Sorted so the output is stable
+		sort.Strings(params)
+		redacted += "?" + strings.Join(params, "&")
+	}
+	return redacted
+}
+
+// runSnooze mutes notifications from all sessions for a duration, or clears the snooze with "off"
+func runSnooze(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: claude-code-ntfy snooze DURATION|off\n")
+		return 1
+	}
+
+	path := config.SnoozePath()
+	if path == "" {
+		fmt.Fprintf(os.Stderr, "Error: could not determine snooze file location\n")
+		return 1
+	}
+
+	if args[0] == "off" {
+		if err := notification.ClearSnooze(path); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Println("Notifications resumed")
+		return 0
+	}
+
+	duration, err := time.ParseDuration(args[0])
+	if err != nil || duration <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: invalid snooze duration %q (e.g. 30m, 1h)\n", args[0])
+		return 1
+	}
+
+	until := time.Now().Add(duration)
+	if err := notification.WriteSnooze(path, until); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Notifications snoozed until %s\n", until.Format("15:04"))
+	return 0
+}
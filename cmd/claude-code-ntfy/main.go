@@ -2,6 +2,8 @@ package main
 
 import (
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -10,10 +12,18 @@ import (
 	"syscall"
 
 	"github.com/Veraticus/claude-code-ntfy/pkg/config"
+	"github.com/Veraticus/claude-code-ntfy/pkg/logging"
 	flag "github.com/spf13/pflag"
 )
 
 func main() {
+	// Handle our own subcommands before anything is passed through to Claude
+	if len(os.Args) > 1 {
+		if code, ok := runCommand(os.Args[1], os.Args[2:]); ok {
+			os.Exit(code)
+		}
+	}
+
 	// Parse our flags and separate Claude's flags
 	var (
 		configPath string
@@ -110,35 +120,20 @@ func main() {
 	// Use the manually parsed Claude args
 	userArgs := claudeArgs
 
-	// Debug output
-	if os.Getenv("CLAUDE_NOTIFY_DEBUG") == "1" {
-		fmt.Fprintf(os.Stderr, "claude-code-ntfy: Parsed claude args: %v\n", claudeArgs)
+	// Log to the configured destination from here on
+	closeLog, err := logging.Setup(cfg.LogFile, cfg.LogLevel)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error setting up log: %v\n", err)
+		os.Exit(1)
 	}
+	defer func() { _ = closeLog() }()
 
-	var command string
+	slog.Debug("parsed claude args", "args", claudeArgs)
 
-	// Determine claude path
-	if cfg.ClaudePath != "" {
-		// Use configured path directly - don't validate, let it fail at execution if wrong
-		command = cfg.ClaudePath
-		if os.Getenv("CLAUDE_NOTIFY_DEBUG") == "1" {
-			fmt.Fprintf(os.Stderr, "claude-code-ntfy: Using configured claude path: %s\n", command)
-		}
-	} else {
-		// Try to find claude in PATH, excluding ourselves
-		claudePath, err := findClaude()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			fmt.Fprintf(os.Stderr, "\nYou can fix this by:\n")
-			fmt.Fprintf(os.Stderr, "1. Setting claude_path in your config file (~/.config/claude-code-ntfy/config.yaml)\n")
-			fmt.Fprintf(os.Stderr, "2. Setting CLAUDE_NOTIFY_CLAUDE_PATH environment variable\n")
-			fmt.Fprintf(os.Stderr, "3. Ensuring the real claude is in your PATH\n")
-			os.Exit(1)
-		}
-		command = claudePath
-		if os.Getenv("CLAUDE_NOTIFY_DEBUG") == "1" {
-			fmt.Fprintf(os.Stderr, "claude-code-ntfy: Found claude in PATH at: %s\n", command)
-		}
+	// Locate claude before building anything, so a missing binary only prints guidance
+	command, code := resolveClaudeCommand(cfg, os.Stderr)
+	if code != 0 {
+		os.Exit(code)
 	}
 
 	// Merge default args with user args
@@ -181,11 +176,7 @@ func main() {
 		os.Exit(130)
 	}()
 
-	// Debug output if verbose
-	if os.Getenv("CLAUDE_NOTIFY_DEBUG") == "1" {
-		fmt.Fprintf(os.Stderr, "claude-code-ntfy: Starting claude with args: %v\n", args)
-		fmt.Fprintf(os.Stderr, "claude-code-ntfy: Config: quiet=%v, topic=%q\n", cfg.Quiet, cfg.NtfyTopic)
-	}
+	slog.Debug("starting claude", "args", args, "quiet", cfg.Quiet, "topic", cfg.NtfyTopic)
 
 	// Run the application
 	if err := app.Run(command, args); err != nil {
@@ -204,6 +195,13 @@ func printUsage() {
 	fmt.Println("claude-code-ntfy - Claude Code wrapper with notifications")
 	fmt.Println()
 	fmt.Println("Usage: claude-code-ntfy [OPTIONS] [CLAUDE_ARGS...]")
+	fmt.Println("       claude-code-ntfy COMMAND [--config PATH]")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  snooze DURATION|off  Mute notifications from all sessions for a while")
+	fmt.Println("  subscribe            Print notifications arriving on the configured ntfy topic")
+	fmt.Println("  test                 Send a test notification to check the configuration")
+	fmt.Println("  validate             Check the configuration and print the resolved settings")
 	fmt.Println()
 	fmt.Println("Options:")
 	fmt.Println("      --config string   Path to config file")
@@ -215,16 +213,125 @@ func printUsage() {
 	fmt.Println("Environment Variables:")
 	fmt.Println("  CLAUDE_NOTIFY_TOPIC       Ntfy topic for notifications")
 	fmt.Println("  CLAUDE_NOTIFY_SERVER      Ntfy server URL (default: https://ntfy.sh)")
+	fmt.Println("  CLAUDE_NOTIFY_BACKENDS    Notification backends, comma-separated: ntfy, stdout, desktop, webhook (default: ntfy)")
+	fmt.Println("  CLAUDE_NOTIFY_FALLBACK_BACKEND  Backend used only when sending to the others fails")
+	fmt.Println("  CLAUDE_NOTIFY_DESKTOP_RETRIES  Retry a failed desktop notification this many times (default: 0)")
+	fmt.Println("  CLAUDE_NOTIFY_CLICK_FOCUS_TERMINAL  Focus the terminal and Claude's tmux pane when a desktop notification is clicked")
+	fmt.Println("  CLAUDE_NOTIFY_MACOS_NOTIFIER  macOS desktop notifications via auto, terminal-notifier or osascript (default: auto)")
+	fmt.Println("  CLAUDE_NOTIFY_WEBHOOK_URL  URL the webhook backend sends notifications to")
+	fmt.Println("  CLAUDE_NOTIFY_WEBHOOK_METHOD  HTTP method for the webhook (default: POST)")
+	fmt.Println("  CLAUDE_NOTIFY_WEBHOOK_HEADERS  Extra webhook headers (comma-separated Name=value)")
+	fmt.Println("  CLAUDE_NOTIFY_WEBHOOK_BODY_TEMPLATE  Go template for the webhook body; {{json .Message}} quotes for JSON")
+	fmt.Println("  CLAUDE_NOTIFY_USERNAME    Basic Auth username for a self-hosted ntfy server")
+	fmt.Println("  CLAUDE_NOTIFY_PASSWORD    Basic Auth password for a self-hosted ntfy server")
+	fmt.Println("  CLAUDE_NOTIFY_TIMEOUT     Timeout for each ntfy request (default: 10s)")
+	fmt.Println("  CLAUDE_NOTIFY_PROXY       Proxy URL for ntfy requests (default: HTTP_PROXY/HTTPS_PROXY)")
+	fmt.Println("  CLAUDE_NOTIFY_RETRY_ATTEMPTS  Retry failed sends this many times, at most 10 (default: 0)")
+	fmt.Println("  CLAUDE_NOTIFY_RETRY_BASE_DELAY  Delay before the first retry, doubled each time up to 1m (default: 1s)")
+	fmt.Println("  CLAUDE_NOTIFY_MARKDOWN    Render messages as markdown: on, off or auto (default: off)")
+	fmt.Println("  CLAUDE_NOTIFY_DISABLE_KEEPALIVE  Open a new connection to ntfy for every notification (debugging)")
+	fmt.Println("  CLAUDE_NOTIFY_MESSAGE_PREFIX  Text added before every notification message")
+	fmt.Println("  CLAUDE_NOTIFY_MESSAGE_SUFFIX  Text added after every notification message")
+	fmt.Println("  CLAUDE_NOTIFY_MESSAGE_FROM_TITLE  Use the terminal title as the notification message")
+	fmt.Println("  CLAUDE_NOTIFY_MAX_TERMINAL_TITLE_LENGTH  Cut longer terminal titles in notification titles (default: 0, no limit)")
+	fmt.Println("  CLAUDE_NOTIFY_INCLUDE_GIT_CONTEXT  Label notifications with the git repo and branch")
+	fmt.Println("  CLAUDE_NOTIFY_FAIL_ON_NOTIFY_ERROR  Exit 1 if any notification failed, even when Claude succeeded")
+	fmt.Println("  CLAUDE_NOTIFY_STATS_ON_EXIT  Print notification counts per pattern when Claude exits")
+	fmt.Println("  CLAUDE_NOTIFY_IN_CI       Send notifications in CI too, where they are only printed by default")
+	fmt.Println("  CLAUDE_NOTIFY_EXIT_STATS  Print how long Claude ran and its CPU time when it exits")
+	fmt.Println("  CLAUDE_NOTIFY_ON_EXIT     Notify when Claude exits, with its exit code")
+	fmt.Println("  CLAUDE_NOTIFY_INTERNAL_ERRORS  Notify about failed sends and I/O errors of claude-code-ntfy itself")
+	fmt.Println("  CLAUDE_NOTIFY_LOG_LEVEL   Lowest log level to write: debug, info, warn or error (default: warn)")
+	fmt.Println("  CLAUDE_NOTIFY_LOG_FILE    Append log records to this file instead of stderr")
+	fmt.Println("  CLAUDE_NOTIFY_SESSION_LOG  Append everything Claude outputs to this file")
+	fmt.Println("  CLAUDE_NOTIFY_CLICK_URL   URL template opened when a notification is tapped")
+	fmt.Println("  CLAUDE_NOTIFY_INCLUDE_SESSION_ID  Tag notifications with a random id for this run")
+	fmt.Println("  CLAUDE_NOTIFY_INCLUDE_SEQUENCE  Append #1, #2, ... to notification titles")
+	fmt.Println("  CLAUDE_NOTIFY_PRE_HOOK    Command given each notification as JSON; may modify or veto it")
+	fmt.Println("  CLAUDE_NOTIFY_POST_HOOK   Shell command run after each notification is sent")
 	fmt.Println("  CLAUDE_NOTIFY_BACKSTOP_TIMEOUT  Inactivity timeout (default: 30s)")
+	fmt.Println("  CLAUDE_NOTIFY_BACKSTOP_MIN_TIMEOUT  Shrink the inactivity timeout to this as the session ages")
+	fmt.Println("  CLAUDE_NOTIFY_BACKSTOP_DECAY  How long the shrink to the minimum timeout takes")
+	fmt.Println("  CLAUDE_NOTIFY_DIGEST_INTERVAL  Send one summary of notifications this often instead of each one (default: off)")
+	fmt.Println("  CLAUDE_NOTIFY_HEARTBEAT_INTERVAL  Send a \"still running\" notification this often (default: off)")
+	fmt.Println("  CLAUDE_NOTIFY_MAX_RUNTIME  Stop Claude and notify once it has run this long (default: off)")
+	fmt.Println("  CLAUDE_NOTIFY_FIRST_OUTPUT_TIMEOUT  Notify if Claude produces no visible output after start (default: off)")
+	fmt.Println("  CLAUDE_NOTIFY_HANG_TIMEOUT  Notify if output is only a spinner for this long (default: off)")
 	fmt.Println("  CLAUDE_NOTIFY_QUIET       Disable notifications (true/false)")
 	fmt.Println("  CLAUDE_NOTIFY_STARTUP     Send startup notification (default: true)")
+	fmt.Println("  CLAUDE_NOTIFY_STARTUP_INCLUDE_COMMAND  Show the claude command line (secrets redacted) at startup")
+	fmt.Println("  CLAUDE_NOTIFY_STARTUP_COALESCE_WINDOW  Drop the startup notification if Claude's output triggers one within this window")
 	fmt.Println("  CLAUDE_NOTIFY_DEFAULT_ARGS  Default Claude args (comma-separated)")
+	fmt.Println("  CLAUDE_NOTIFY_SUPPRESS_UNTIL_PROMPT  No notifications (besides startup) until the first prompt is submitted")
+	fmt.Println("  CLAUDE_NOTIFY_TMUX_PANE_AWARE  Skip notifications while Claude's tmux pane is active")
+	fmt.Println("  CLAUDE_NOTIFY_RESPECT_FRONTMOST_APP  On macOS, skip notifications while the terminal is the frontmost app")
+	fmt.Println("  CLAUDE_NOTIFY_ALLOW_NESTED  Run claude without notifications inside another wrapper session")
 	fmt.Println("  CLAUDE_NOTIFY_CONFIG      Path to config file")
 	fmt.Println("  CLAUDE_NOTIFY_CLAUDE_PATH  Path to the real claude binary")
 	fmt.Println()
 	fmt.Println("Configuration file: ~/.config/claude-code-ntfy/config.yaml")
 }
 
+// validateClaudePath checks that the configured claude path is an executable file.
+// A bare command name is looked up in PATH, just as exec would.
+func validateClaudePath(path string) error {
+	if !strings.ContainsRune(path, filepath.Separator) {
+		if _, err := exec.LookPath(path); err != nil {
+			return fmt.Errorf("configured claude_path %q not found in PATH", path)
+		}
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("configured claude_path %q does not exist", path)
+		}
+		return fmt.Errorf("configured claude_path %q is not accessible: %w", path, err)
+	}
+
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("configured claude_path %q is not a regular file", path)
+	}
+
+	if info.Mode()&0111 == 0 {
+		return fmt.Errorf("configured claude_path %q is not executable", path)
+	}
+
+	return nil
+}
+
+// resolveClaudeCommand determines which claude binary to run. If none can be found it
+// writes the error and how to fix it to stderr, and returns the exit code to use.
+func resolveClaudeCommand(cfg *config.Config, stderr io.Writer) (string, int) {
+	if cfg.ClaudePath != "" {
+		// Fail early with guidance rather than with an exec error from the PTY
+		if err := validateClaudePath(cfg.ClaudePath); err != nil {
+			_, _ = fmt.Fprintf(stderr, "Error: %v\n", err)
+			_, _ = fmt.Fprintf(stderr, "\nYou can fix this by:\n")
+			_, _ = fmt.Fprintf(stderr, "1. Pointing claude_path in your config file (~/.config/claude-code-ntfy/config.yaml) at the real claude binary\n")
+			_, _ = fmt.Fprintf(stderr, "2. Pointing CLAUDE_NOTIFY_CLAUDE_PATH at the real claude binary\n")
+			_, _ = fmt.Fprintf(stderr, "3. Unsetting both so claude is found in your PATH\n")
+			return "", 1
+		}
+		slog.Debug("using configured claude path", "path", cfg.ClaudePath)
+		return cfg.ClaudePath, 0
+	}
+
+	// Try to find claude in PATH, excluding ourselves
+	claudePath, err := findClaude()
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "Error: %v\n", err)
+		_, _ = fmt.Fprintf(stderr, "\nYou can fix this by:\n")
+		_, _ = fmt.Fprintf(stderr, "1. Setting claude_path in your config file (~/.config/claude-code-ntfy/config.yaml)\n")
+		_, _ = fmt.Fprintf(stderr, "2. Setting CLAUDE_NOTIFY_CLAUDE_PATH environment variable\n")
+		_, _ = fmt.Fprintf(stderr, "3. Ensuring the real claude is in your PATH\n")
+		return "", 1
+	}
+	slog.Debug("found claude in PATH", "path", claudePath)
+	return claudePath, 0
+}
+
 // findClaude searches for the real claude binary in PATH, excluding ourselves
 func findClaude() (string, error) {
 	// Get our own executable path to exclude it
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Veraticus/claude-code-ntfy/pkg/config"
+)
+
+func TestValidateClaudePath(t *testing.T) {
+	dir := t.TempDir()
+
+	executable := filepath.Join(dir, "claude")
+	if err := os.WriteFile(executable, []byte("#!/bin/sh\n"), 0700); err != nil {
+		t.Fatalf("failed to write executable: %v", err)
+	}
+
+	notExecutable := filepath.Join(dir, "claude.txt")
+	if err := os.WriteFile(notExecutable, []byte("not a program"), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	t.Setenv("PATH", dir)
+
+	tests := []struct {
+		name        string
+		path        string
+		errContains string
+	}{
+		{name: "executable file", path: executable},
+		{name: "bare name found in PATH", path: "claude"},
+		{name: "nonexistent path", path: filepath.Join(dir, "missing"), errContains: "does not exist"},
+		{name: "not executable", path: notExecutable, errContains: "is not executable"},
+		{name: "directory", path: dir, errContains: "is not a regular file"},
+		{name: "bare name missing from PATH", path: "claude-missing", errContains: "not found in PATH"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateClaudePath(tt.path)
+			if tt.errContains == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected error but got none")
+			}
+			if !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("expected error containing %q but got %q", tt.errContains, err.Error())
+			}
+			if !strings.Contains(err.Error(), "claude_path") {
+				t.Errorf("expected error to name the claude_path setting, got %q", err.Error())
+			}
+		})
+	}
+}
+
+func TestResolveClaudeCommand_NotFound(t *testing.T) {
+	// An empty directory on PATH guarantees there is no claude to find
+	t.Setenv("PATH", t.TempDir())
+
+	cfg := &config.Config{
+		DefaultClaudeArgs: []string{"--verbose"},
+	}
+
+	var stderr bytes.Buffer
+	command, code := resolveClaudeCommand(cfg, &stderr)
+
+	if code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+	if command != "" {
+		t.Errorf("expected no command, got %q", command)
+	}
+	for _, want := range []string{"claude not found in PATH", "You can fix this by", "CLAUDE_NOTIFY_CLAUDE_PATH"} {
+		if !strings.Contains(stderr.String(), want) {
+			t.Errorf("expected guidance to contain %q, got %q", want, stderr.String())
+		}
+	}
+}
+
+func TestResolveClaudeCommand_Found(t *testing.T) {
+	dir := t.TempDir()
+	claude := filepath.Join(dir, "claude")
+	if err := os.WriteFile(claude, []byte("#!/bin/sh\n"), 0700); err != nil {
+		t.Fatalf("failed to write executable: %v", err)
+	}
+	t.Setenv("PATH", dir)
+
+	var stderr bytes.Buffer
+	command, code := resolveClaudeCommand(&config.Config{}, &stderr)
+
+	if code != 0 || command != claude {
+		t.Errorf("expected %q with exit code 0, got %q with %d", claude, command, code)
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("expected no output, got %q", stderr.String())
+	}
+}
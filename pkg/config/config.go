@@ -2,11 +2,16 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
+	"github.com/Veraticus/claude-code-ntfy/pkg/notification"
 	"gopkg.in/yaml.v3"
 )
 
@@ -16,14 +21,147 @@ type Config struct {
 	NtfyTopic  string `yaml:"ntfy_topic" env:"CLAUDE_NOTIFY_TOPIC"`
 	NtfyServer string `yaml:"ntfy_server" env:"CLAUDE_NOTIFY_SERVER"`
 
+	// Where notifications are delivered: "ntfy", "stdout", "desktop" and/or "webhook" (default: ntfy)
+	Backends []string `yaml:"backends" env:"CLAUDE_NOTIFY_BACKENDS"`
+
+	// Backend that is only used when sending to the backends above fails
+	FallbackBackend string `yaml:"fallback_backend" env:"CLAUDE_NOTIFY_FALLBACK_BACKEND"`
+
+	// Webhook backend - body template is rendered with the notification fields
+	WebhookURL          string            `yaml:"webhook_url" env:"CLAUDE_NOTIFY_WEBHOOK_URL"`
+	WebhookMethod       string            `yaml:"webhook_method" env:"CLAUDE_NOTIFY_WEBHOOK_METHOD"`
+	WebhookHeaders      map[string]string `yaml:"webhook_headers" env:"CLAUDE_NOTIFY_WEBHOOK_HEADERS"`
+	WebhookBodyTemplate string            `yaml:"webhook_body_template" env:"CLAUDE_NOTIFY_WEBHOOK_BODY_TEMPLATE"`
+
+	// Desktop backend on macOS: "auto" or "terminal-notifier" prefer terminal-notifier, "osascript" never uses it
+	MacOSNotifier string `yaml:"macos_notifier" env:"CLAUDE_NOTIFY_MACOS_NOTIFIER"`
+
+	// Desktop backend - retry a failed notification command this many times, with backoff
+	DesktopRetries int `yaml:"desktop_retries" env:"CLAUDE_NOTIFY_DESKTOP_RETRIES"`
+
+	// Desktop backend - clicking a notification brings the terminal, and Claude's tmux pane, to the front
+	ClickFocusTerminal bool `yaml:"click_focus_terminal" env:"CLAUDE_NOTIFY_CLICK_FOCUS_TERMINAL"`
+
+	// Retry failed sends with exponential backoff and jitter (0 attempts disables)
+	RetryAttempts  int           `yaml:"retry_attempts" env:"CLAUDE_NOTIFY_RETRY_ATTEMPTS"`
+	RetryBaseDelay time.Duration `yaml:"retry_base_delay" env:"CLAUDE_NOTIFY_RETRY_BASE_DELAY"`
+
+	// Render messages as markdown: "on", "off" or "auto" to detect lists and code blocks
+	Markdown string `yaml:"markdown" env:"CLAUDE_NOTIFY_MARKDOWN"`
+
+	// HTTP Basic Auth for self-hosted servers; only sent when both are set
+	NtfyUsername string `yaml:"ntfy_username" env:"CLAUDE_NOTIFY_USERNAME"`
+	NtfyPassword string `yaml:"ntfy_password" env:"CLAUDE_NOTIFY_PASSWORD"`
+
+	// Timeout for each request to ntfy, including connecting and reading the response
+	NtfyTimeout time.Duration `yaml:"ntfy_timeout" env:"CLAUDE_NOTIFY_TIMEOUT"`
+
+	// Proxy for requests to ntfy; HTTP_PROXY/HTTPS_PROXY are used when unset
+	NtfyProxy string `yaml:"ntfy_proxy" env:"CLAUDE_NOTIFY_PROXY"`
+
+	// Debugging - open a fresh connection to ntfy for every notification
+	NtfyDisableKeepAlive bool `yaml:"ntfy_disable_keepalive" env:"CLAUDE_NOTIFY_DISABLE_KEEPALIVE"`
+
+	// Message formatting - text added around every notification message
+	MessagePrefix string `yaml:"message_prefix" env:"CLAUDE_NOTIFY_MESSAGE_PREFIX"`
+	MessageSuffix string `yaml:"message_suffix" env:"CLAUDE_NOTIFY_MESSAGE_SUFFIX"`
+
+	// URL template opened when a notification is tapped, e.g. "https://ci.example.com/{{.Pattern}}"
+	ClickURL string `yaml:"click_url" env:"CLAUDE_NOTIFY_CLICK_URL"`
+
+	// Label notifications with the git repository and branch instead of the directory name
+	IncludeGitContext bool `yaml:"include_git_context" env:"CLAUDE_NOTIFY_INCLUDE_GIT_CONTEXT"`
+
+	// Tag every notification with a random id for this run
+	IncludeSessionID bool `yaml:"include_session_id" env:"CLAUDE_NOTIFY_INCLUDE_SESSION_ID"`
+
+	// Append an increasing sequence number to every notification title
+	IncludeSequence bool `yaml:"include_sequence" env:"CLAUDE_NOTIFY_INCLUDE_SEQUENCE"`
+
+	// Exit non-zero if any notification failed to send, even when Claude succeeded
+	FailOnNotifyError bool `yaml:"fail_on_notify_error" env:"CLAUDE_NOTIFY_FAIL_ON_NOTIFY_ERROR"`
+
+	// Print per-pattern notification counts when Claude exits
+	StatsOnExit bool `yaml:"stats_on_exit" env:"CLAUDE_NOTIFY_STATS_ON_EXIT"`
+
+	// Send notifications as usual in CI, where by default they are only printed
+	NotifyInCI bool `yaml:"notify_in_ci" env:"CLAUDE_NOTIFY_IN_CI"`
+
+	// Send a low priority notification about serious errors of claude-code-ntfy itself
+	NotifyInternalErrors bool `yaml:"notify_internal_errors" env:"CLAUDE_NOTIFY_INTERNAL_ERRORS"`
+
+	// Notify when Claude exits, with its exit code
+	NotifyOnExit bool `yaml:"notify_on_exit" env:"CLAUDE_NOTIFY_ON_EXIT"`
+
+	// Print how long Claude ran and the CPU time it used when it exits
+	ExitStats bool `yaml:"exit_stats" env:"CLAUDE_NOTIFY_EXIT_STATS"`
+
+	// Lowest level of log records to write: debug, info, warn or error (default: warn)
+	LogLevel string `yaml:"log_level" env:"CLAUDE_NOTIFY_LOG_LEVEL"`
+
+	// Write log records to this file instead of stderr
+	LogFile string `yaml:"log_file" env:"CLAUDE_NOTIFY_LOG_FILE"`
+
+	// Append everything Claude outputs to this file, for debugging notifications
+	SessionLog string `yaml:"session_log" env:"CLAUDE_NOTIFY_SESSION_LOG"`
+
+	// Cut terminal titles longer than this many characters in notification titles (0: no limit)
+	MaxTerminalTitleLength int `yaml:"max_terminal_title_length" env:"CLAUDE_NOTIFY_MAX_TERMINAL_TITLE_LENGTH"`
+
+	// Use the terminal title as the notification message when Claude has set one
+	MessageFromTitle bool `yaml:"message_from_title" env:"CLAUDE_NOTIFY_MESSAGE_FROM_TITLE"`
+
+	// Hooks - shell commands run before and after each notification is sent
+	PreHook  string `yaml:"pre_hook" env:"CLAUDE_NOTIFY_PRE_HOOK"`
+	PostHook string `yaml:"post_hook" env:"CLAUDE_NOTIFY_POST_HOOK"`
+
 	// Behavior flags
 	Quiet             bool     `yaml:"quiet" env:"CLAUDE_NOTIFY_QUIET"`
 	StartupNotify     bool     `yaml:"startup_notify" env:"CLAUDE_NOTIFY_STARTUP"`
 	DefaultClaudeArgs []string `yaml:"default_claude_args"`
 
+	// Show the claude command line, with secrets redacted, in the startup notification
+	StartupIncludeCommand bool `yaml:"startup_include_command" env:"CLAUDE_NOTIFY_STARTUP_INCLUDE_COMMAND"`
+
+	// Hold the startup notification back this long and drop it if another
+	// notification is sent first (0 sends it immediately)
+	StartupCoalesceWindow time.Duration `yaml:"startup_coalesce_window" env:"CLAUDE_NOTIFY_STARTUP_COALESCE_WINDOW"`
+
 	// Backstop notification - send notification after inactivity
 	BackstopTimeout time.Duration `yaml:"backstop_timeout" env:"CLAUDE_NOTIFY_BACKSTOP_TIMEOUT"`
 
+	// Shrink the backstop timeout to backstop_min_timeout over the first backstop_decay of a session
+	BackstopMinTimeout time.Duration `yaml:"backstop_min_timeout" env:"CLAUDE_NOTIFY_BACKSTOP_MIN_TIMEOUT"`
+	BackstopDecay      time.Duration `yaml:"backstop_decay" env:"CLAUDE_NOTIFY_BACKSTOP_DECAY"`
+
+	// Stop Claude and notify once it has run this long (0 disables)
+	MaxRuntime time.Duration `yaml:"max_runtime" env:"CLAUDE_NOTIFY_MAX_RUNTIME"`
+
+	// Digest - hold notifications back and send one summary this often (0 disables)
+	DigestInterval time.Duration `yaml:"digest_interval" env:"CLAUDE_NOTIFY_DIGEST_INTERVAL"`
+
+	// Heartbeat - send a periodic "still running" notification (0 disables)
+	HeartbeatInterval time.Duration `yaml:"heartbeat_interval" env:"CLAUDE_NOTIFY_HEARTBEAT_INTERVAL"`
+
+	// First output watchdog - notify if Claude produces nothing this long after start (0 disables)
+	FirstOutputTimeout time.Duration `yaml:"first_output_timeout" env:"CLAUDE_NOTIFY_FIRST_OUTPUT_TIMEOUT"`
+
+	// Notify once when output has only been spinner frames (lines rewritten with \r) this long (0 disables)
+	HangTimeout time.Duration `yaml:"hang_timeout" env:"CLAUDE_NOTIFY_HANG_TIMEOUT"`
+
+	// Run Claude straight through (no monitoring or notifications) when already
+	// inside a claude-code-ntfy session instead of failing
+	AllowNested bool `yaml:"allow_nested" env:"CLAUDE_NOTIFY_ALLOW_NESTED"`
+
+	// Drop notifications until the first prompt is submitted
+	SuppressUntilPrompt bool `yaml:"suppress_until_prompt" env:"CLAUDE_NOTIFY_SUPPRESS_UNTIL_PROMPT"`
+
+	// Inside tmux, skip notifications while Claude's pane is the one being viewed
+	TmuxPaneAware bool `yaml:"tmux_pane_aware" env:"CLAUDE_NOTIFY_TMUX_PANE_AWARE"`
+
+	// macOS - skip notifications while the terminal Claude runs in is the frontmost app
+	RespectFrontmostApp bool `yaml:"respect_frontmost_app" env:"CLAUDE_NOTIFY_RESPECT_FRONTMOST_APP"`
+
 	// Claude path configuration
 	ClaudePath string `yaml:"claude_path" env:"CLAUDE_NOTIFY_CLAUDE_PATH"`
 }
@@ -32,6 +170,8 @@ type Config struct {
 func DefaultConfig() *Config {
 	return &Config{
 		NtfyServer:      "https://ntfy.sh",
+		NtfyTimeout:     10 * time.Second,
+		RetryBaseDelay:  time.Second,
 		BackstopTimeout: 30 * time.Second,
 		StartupNotify:   true, // Default to true so users know notifications are working
 	}
@@ -54,6 +194,12 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("failed to load from environment: %w", err)
 	}
 
+	// Nobody is watching for notifications in CI, so only log them
+	if IsCI() && !cfg.NotifyInCI {
+		cfg.Backends = []string{"stdout"}
+		cfg.FallbackBackend = ""
+	}
+
 	// Validate configuration
 	if err := validate(cfg); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
@@ -62,6 +208,26 @@ func Load() (*Config, error) {
 	return cfg, nil
 }
 
+// ciEnvVars are set by CI systems for their jobs
+var ciEnvVars = []string{"CI", "GITHUB_ACTIONS", "GITLAB_CI", "BUILDKITE", "CIRCLECI", "JENKINS_URL", "TF_BUILD"}
+
+// IsCI reports whether we are running in a CI job
+func IsCI() bool {
+	for _, name := range ciEnvVars {
+		switch strings.ToLower(os.Getenv(name)) {
+		case "", "0", "false":
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// Path returns the config file Load reads, which may not exist
+func Path() string {
+	return getConfigPath()
+}
+
 // getConfigPath returns the config file path
 func getConfigPath() string {
 	// Check for explicit config path
@@ -82,6 +248,21 @@ func getConfigPath() string {
 	return ""
 }
 
+// SnoozePath returns the path of the file recording an active snooze
+func SnoozePath() string {
+	// Check XDG state directory
+	if xdgState := os.Getenv("XDG_STATE_HOME"); xdgState != "" {
+		return filepath.Join(xdgState, "claude-code-ntfy", "snooze")
+	}
+
+	// Fall back to home directory
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".local", "state", "claude-code-ntfy", "snooze")
+	}
+
+	return ""
+}
+
 // loadFromFile loads configuration from a YAML file
 func loadFromFile(cfg *Config, path string) error {
 	// #nosec G304 - The config file path comes from trusted sources (env var or standard locations)
@@ -103,34 +284,176 @@ func loadFromEnv(cfg *Config) error {
 		cfg.NtfyServer = server
 	}
 
-	if timeout := os.Getenv("CLAUDE_NOTIFY_BACKSTOP_TIMEOUT"); timeout != "" {
-		d, err := time.ParseDuration(timeout)
-		if err != nil {
-			return fmt.Errorf("invalid CLAUDE_NOTIFY_BACKSTOP_TIMEOUT: %w", err)
-		}
-		cfg.BackstopTimeout = d
+	if err := loadIntFromEnv("CLAUDE_NOTIFY_RETRY_ATTEMPTS", &cfg.RetryAttempts); err != nil {
+		return err
 	}
 
-	if quiet := os.Getenv("CLAUDE_NOTIFY_QUIET"); quiet != "" {
-		switch quiet {
-		case "true", "1", "yes":
-			cfg.Quiet = true
-		case "false", "0", "no":
-			cfg.Quiet = false
-		default:
-			return fmt.Errorf("invalid CLAUDE_NOTIFY_QUIET value: %q (use true/false)", quiet)
-		}
+	if err := loadDurationFromEnv("CLAUDE_NOTIFY_RETRY_BASE_DELAY", &cfg.RetryBaseDelay); err != nil {
+		return err
 	}
 
-	if startup := os.Getenv("CLAUDE_NOTIFY_STARTUP"); startup != "" {
-		switch startup {
-		case "true", "1", "yes":
-			cfg.StartupNotify = true
-		case "false", "0", "no":
-			cfg.StartupNotify = false
-		default:
-			return fmt.Errorf("invalid CLAUDE_NOTIFY_STARTUP value: %q (use true/false)", startup)
-		}
+	if markdown := os.Getenv("CLAUDE_NOTIFY_MARKDOWN"); markdown != "" {
+		cfg.Markdown = markdown
+	}
+
+	if username := os.Getenv("CLAUDE_NOTIFY_USERNAME"); username != "" {
+		cfg.NtfyUsername = username
+	}
+
+	if password := os.Getenv("CLAUDE_NOTIFY_PASSWORD"); password != "" {
+		cfg.NtfyPassword = password
+	}
+
+	if err := loadDurationFromEnv("CLAUDE_NOTIFY_TIMEOUT", &cfg.NtfyTimeout); err != nil {
+		return err
+	}
+
+	if proxy := os.Getenv("CLAUDE_NOTIFY_PROXY"); proxy != "" {
+		cfg.NtfyProxy = proxy
+	}
+
+	if err := loadBoolFromEnv("CLAUDE_NOTIFY_DISABLE_KEEPALIVE", &cfg.NtfyDisableKeepAlive); err != nil {
+		return err
+	}
+
+	if prefix := os.Getenv("CLAUDE_NOTIFY_MESSAGE_PREFIX"); prefix != "" {
+		cfg.MessagePrefix = prefix
+	}
+
+	if suffix := os.Getenv("CLAUDE_NOTIFY_MESSAGE_SUFFIX"); suffix != "" {
+		cfg.MessageSuffix = suffix
+	}
+
+	if preHook := os.Getenv("CLAUDE_NOTIFY_PRE_HOOK"); preHook != "" {
+		cfg.PreHook = preHook
+	}
+
+	if postHook := os.Getenv("CLAUDE_NOTIFY_POST_HOOK"); postHook != "" {
+		cfg.PostHook = postHook
+	}
+
+	if err := loadDurationFromEnv("CLAUDE_NOTIFY_STARTUP_COALESCE_WINDOW", &cfg.StartupCoalesceWindow); err != nil {
+		return err
+	}
+
+	if err := loadDurationFromEnv("CLAUDE_NOTIFY_BACKSTOP_TIMEOUT", &cfg.BackstopTimeout); err != nil {
+		return err
+	}
+
+	if err := loadDurationFromEnv("CLAUDE_NOTIFY_BACKSTOP_MIN_TIMEOUT", &cfg.BackstopMinTimeout); err != nil {
+		return err
+	}
+
+	if err := loadDurationFromEnv("CLAUDE_NOTIFY_BACKSTOP_DECAY", &cfg.BackstopDecay); err != nil {
+		return err
+	}
+
+	if err := loadDurationFromEnv("CLAUDE_NOTIFY_HEARTBEAT_INTERVAL", &cfg.HeartbeatInterval); err != nil {
+		return err
+	}
+
+	if err := loadDurationFromEnv("CLAUDE_NOTIFY_DIGEST_INTERVAL", &cfg.DigestInterval); err != nil {
+		return err
+	}
+
+	if err := loadDurationFromEnv("CLAUDE_NOTIFY_FIRST_OUTPUT_TIMEOUT", &cfg.FirstOutputTimeout); err != nil {
+		return err
+	}
+
+	if err := loadDurationFromEnv("CLAUDE_NOTIFY_HANG_TIMEOUT", &cfg.HangTimeout); err != nil {
+		return err
+	}
+
+	if err := loadDurationFromEnv("CLAUDE_NOTIFY_MAX_RUNTIME", &cfg.MaxRuntime); err != nil {
+		return err
+	}
+
+	if err := loadBoolFromEnv("CLAUDE_NOTIFY_QUIET", &cfg.Quiet); err != nil {
+		return err
+	}
+
+	if err := loadBoolFromEnv("CLAUDE_NOTIFY_STARTUP", &cfg.StartupNotify); err != nil {
+		return err
+	}
+
+	if err := loadBoolFromEnv("CLAUDE_NOTIFY_STARTUP_INCLUDE_COMMAND", &cfg.StartupIncludeCommand); err != nil {
+		return err
+	}
+
+	if err := loadBoolFromEnv("CLAUDE_NOTIFY_MESSAGE_FROM_TITLE", &cfg.MessageFromTitle); err != nil {
+		return err
+	}
+
+	if err := loadIntFromEnv("CLAUDE_NOTIFY_MAX_TERMINAL_TITLE_LENGTH", &cfg.MaxTerminalTitleLength); err != nil {
+		return err
+	}
+
+	if err := loadBoolFromEnv("CLAUDE_NOTIFY_FAIL_ON_NOTIFY_ERROR", &cfg.FailOnNotifyError); err != nil {
+		return err
+	}
+
+	if err := loadBoolFromEnv("CLAUDE_NOTIFY_STATS_ON_EXIT", &cfg.StatsOnExit); err != nil {
+		return err
+	}
+
+	if err := loadBoolFromEnv("CLAUDE_NOTIFY_EXIT_STATS", &cfg.ExitStats); err != nil {
+		return err
+	}
+
+	if err := loadBoolFromEnv("CLAUDE_NOTIFY_ON_EXIT", &cfg.NotifyOnExit); err != nil {
+		return err
+	}
+
+	if err := loadBoolFromEnv("CLAUDE_NOTIFY_INTERNAL_ERRORS", &cfg.NotifyInternalErrors); err != nil {
+		return err
+	}
+
+	if err := loadBoolFromEnv("CLAUDE_NOTIFY_IN_CI", &cfg.NotifyInCI); err != nil {
+		return err
+	}
+
+	if logLevel := os.Getenv("CLAUDE_NOTIFY_LOG_LEVEL"); logLevel != "" {
+		cfg.LogLevel = logLevel
+	}
+
+	if logFile := os.Getenv("CLAUDE_NOTIFY_LOG_FILE"); logFile != "" {
+		cfg.LogFile = logFile
+	}
+
+	if sessionLog := os.Getenv("CLAUDE_NOTIFY_SESSION_LOG"); sessionLog != "" {
+		cfg.SessionLog = sessionLog
+	}
+
+	if clickURL := os.Getenv("CLAUDE_NOTIFY_CLICK_URL"); clickURL != "" {
+		cfg.ClickURL = clickURL
+	}
+
+	if err := loadBoolFromEnv("CLAUDE_NOTIFY_INCLUDE_GIT_CONTEXT", &cfg.IncludeGitContext); err != nil {
+		return err
+	}
+
+	if err := loadBoolFromEnv("CLAUDE_NOTIFY_INCLUDE_SESSION_ID", &cfg.IncludeSessionID); err != nil {
+		return err
+	}
+
+	if err := loadBoolFromEnv("CLAUDE_NOTIFY_INCLUDE_SEQUENCE", &cfg.IncludeSequence); err != nil {
+		return err
+	}
+
+	if err := loadBoolFromEnv("CLAUDE_NOTIFY_ALLOW_NESTED", &cfg.AllowNested); err != nil {
+		return err
+	}
+
+	if err := loadBoolFromEnv("CLAUDE_NOTIFY_SUPPRESS_UNTIL_PROMPT", &cfg.SuppressUntilPrompt); err != nil {
+		return err
+	}
+
+	if err := loadBoolFromEnv("CLAUDE_NOTIFY_TMUX_PANE_AWARE", &cfg.TmuxPaneAware); err != nil {
+		return err
+	}
+
+	if err := loadBoolFromEnv("CLAUDE_NOTIFY_RESPECT_FRONTMOST_APP", &cfg.RespectFrontmostApp); err != nil {
+		return err
 	}
 
 	if claudePath := os.Getenv("CLAUDE_NOTIFY_CLAUDE_PATH"); claudePath != "" {
@@ -138,27 +461,180 @@ func loadFromEnv(cfg *Config) error {
 	}
 
 	if defaultArgs := os.Getenv("CLAUDE_NOTIFY_DEFAULT_ARGS"); defaultArgs != "" {
-		// Split by comma and trim whitespace
-		args := strings.Split(defaultArgs, ",")
-		for i, arg := range args {
-			args[i] = strings.TrimSpace(arg)
-		}
-		// Filter out empty strings
-		var filteredArgs []string
-		for _, arg := range args {
-			if arg != "" {
-				filteredArgs = append(filteredArgs, arg)
+		cfg.DefaultClaudeArgs = splitList(defaultArgs)
+	}
+
+	if backends := os.Getenv("CLAUDE_NOTIFY_BACKENDS"); backends != "" {
+		cfg.Backends = splitList(backends)
+	}
+
+	if fallback := os.Getenv("CLAUDE_NOTIFY_FALLBACK_BACKEND"); fallback != "" {
+		cfg.FallbackBackend = fallback
+	}
+
+	if macOSNotifier := os.Getenv("CLAUDE_NOTIFY_MACOS_NOTIFIER"); macOSNotifier != "" {
+		cfg.MacOSNotifier = macOSNotifier
+	}
+
+	if err := loadIntFromEnv("CLAUDE_NOTIFY_DESKTOP_RETRIES", &cfg.DesktopRetries); err != nil {
+		return err
+	}
+
+	if err := loadBoolFromEnv("CLAUDE_NOTIFY_CLICK_FOCUS_TERMINAL", &cfg.ClickFocusTerminal); err != nil {
+		return err
+	}
+
+	if webhookURL := os.Getenv("CLAUDE_NOTIFY_WEBHOOK_URL"); webhookURL != "" {
+		cfg.WebhookURL = webhookURL
+	}
+
+	if webhookMethod := os.Getenv("CLAUDE_NOTIFY_WEBHOOK_METHOD"); webhookMethod != "" {
+		cfg.WebhookMethod = webhookMethod
+	}
+
+	if webhookHeaders := os.Getenv("CLAUDE_NOTIFY_WEBHOOK_HEADERS"); webhookHeaders != "" {
+		// Name=value pairs, comma-separated
+		headers := make(map[string]string)
+		for _, pair := range splitList(webhookHeaders) {
+			name, value, ok := strings.Cut(pair, "=")
+			if !ok || strings.TrimSpace(name) == "" {
+				return fmt.Errorf("invalid CLAUDE_NOTIFY_WEBHOOK_HEADERS entry %q: want Name=value", pair)
 			}
+			headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
 		}
-		cfg.DefaultClaudeArgs = filteredArgs
+		cfg.WebhookHeaders = headers
+	}
+
+	if webhookBody := os.Getenv("CLAUDE_NOTIFY_WEBHOOK_BODY_TEMPLATE"); webhookBody != "" {
+		cfg.WebhookBodyTemplate = webhookBody
 	}
 
 	return nil
 }
 
+// splitList splits a comma-separated value, trimming whitespace and dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// loadBoolFromEnv parses a boolean environment variable into dst if it is set
+func loadBoolFromEnv(name string, dst *bool) error {
+	value := os.Getenv(name)
+	switch value {
+	case "":
+		return nil
+	case "true", "1", "yes":
+		*dst = true
+	case "false", "0", "no":
+		*dst = false
+	default:
+		return fmt.Errorf("invalid %s value: %q (use true/false)", name, value)
+	}
+	return nil
+}
+
+// loadIntFromEnv parses an integer environment variable into dst if it is set
+func loadIntFromEnv(name string, dst *int) error {
+	value := os.Getenv(name)
+	if value == "" {
+		return nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", name, err)
+	}
+	*dst = n
+	return nil
+}
+
+// loadDurationFromEnv parses a duration environment variable into dst if it is set
+func loadDurationFromEnv(name string, dst *time.Duration) error {
+	value := os.Getenv(name)
+	if value == "" {
+		return nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", name, err)
+	}
+	*dst = d
+	return nil
+}
+
+// UsesBackend reports whether notifications are delivered to the named backend,
+// either normally or as the fallback
+func (c *Config) UsesBackend(name string) bool {
+	if c.FallbackBackend == name {
+		return true
+	}
+	if len(c.Backends) == 0 {
+		return name == "ntfy"
+	}
+	return slices.Contains(c.Backends, name)
+}
+
+// validBackend reports whether name is a known notification backend
+func validBackend(name string) bool {
+	switch name {
+	case "ntfy", "stdout", "desktop", "webhook":
+		return true
+	}
+	return false
+}
+
 // validate validates the configuration
 func validate(cfg *Config) error {
-	if cfg.NtfyTopic == "" && !cfg.Quiet {
+	for _, backend := range cfg.Backends {
+		if !validBackend(backend) {
+			return fmt.Errorf("invalid backend %q: must be ntfy, stdout, desktop or webhook", backend)
+		}
+	}
+
+	if cfg.FallbackBackend != "" && !validBackend(cfg.FallbackBackend) {
+		return fmt.Errorf("invalid fallback_backend %q: must be ntfy, stdout, desktop or webhook", cfg.FallbackBackend)
+	}
+
+	if cfg.MaxTerminalTitleLength < 0 {
+		return fmt.Errorf("max_terminal_title_length must be non-negative")
+	}
+
+	if cfg.DesktopRetries < 0 {
+		return fmt.Errorf("desktop_retries must be non-negative")
+	}
+
+	switch cfg.MacOSNotifier {
+	case "", "auto", "terminal-notifier", "osascript":
+	default:
+		return fmt.Errorf("macos_notifier must be one of auto, terminal-notifier or osascript")
+	}
+
+	switch cfg.LogLevel {
+	case "", "debug", "info", "warn", "error":
+	default:
+		return fmt.Errorf("log_level must be one of debug, info, warn or error")
+	}
+
+	if cfg.UsesBackend("webhook") {
+		if u, err := url.Parse(cfg.WebhookURL); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("webhook_url must be a URL such as https://hooks.example.com/claude")
+		}
+	}
+
+	if cfg.WebhookBodyTemplate != "" {
+		if _, err := notification.ParseWebhookTemplate(cfg.WebhookBodyTemplate); err != nil {
+			return err
+		}
+	}
+
+	if cfg.NtfyTopic == "" && !cfg.Quiet && cfg.UsesBackend("ntfy") {
 		return fmt.Errorf("ntfy_topic is required when not in quiet mode")
 	}
 
@@ -166,5 +642,75 @@ func validate(cfg *Config) error {
 		return fmt.Errorf("backstop_timeout must be non-negative")
 	}
 
+	if cfg.BackstopMinTimeout < 0 || cfg.BackstopDecay < 0 {
+		return fmt.Errorf("backstop_min_timeout and backstop_decay must be non-negative")
+	}
+
+	if cfg.BackstopMinTimeout > cfg.BackstopTimeout {
+		return fmt.Errorf("backstop_min_timeout must not exceed backstop_timeout")
+	}
+
+	if cfg.NtfyProxy != "" {
+		if u, err := url.Parse(cfg.NtfyProxy); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("ntfy_proxy must be a URL like http://proxy.example.com:8080")
+		}
+	}
+
+	if (cfg.NtfyUsername == "") != (cfg.NtfyPassword == "") {
+		return fmt.Errorf("ntfy_username and ntfy_password must be set together")
+	}
+
+	if cfg.NtfyTimeout < 0 {
+		return fmt.Errorf("ntfy_timeout must be non-negative")
+	}
+
+	if cfg.RetryAttempts < 0 {
+		return fmt.Errorf("retry_attempts must be non-negative")
+	}
+
+	if cfg.RetryAttempts > 10 {
+		return fmt.Errorf("retry_attempts must be at most 10")
+	}
+
+	if cfg.RetryBaseDelay < 0 {
+		return fmt.Errorf("retry_base_delay must be non-negative")
+	}
+
+	if cfg.StartupCoalesceWindow < 0 {
+		return fmt.Errorf("startup_coalesce_window must be non-negative")
+	}
+
+	if cfg.HeartbeatInterval < 0 {
+		return fmt.Errorf("heartbeat_interval must be non-negative")
+	}
+
+	if cfg.DigestInterval < 0 {
+		return fmt.Errorf("digest_interval must be non-negative")
+	}
+
+	if cfg.FirstOutputTimeout < 0 {
+		return fmt.Errorf("first_output_timeout must be non-negative")
+	}
+
+	if cfg.HangTimeout < 0 {
+		return fmt.Errorf("hang_timeout must be non-negative")
+	}
+
+	if cfg.MaxRuntime < 0 {
+		return fmt.Errorf("max_runtime must be non-negative")
+	}
+
+	switch cfg.Markdown {
+	case "", "on", "off", "auto":
+	default:
+		return fmt.Errorf("markdown must be one of on, off or auto")
+	}
+
+	if cfg.ClickURL != "" {
+		if _, err := template.New("click_url").Parse(cfg.ClickURL); err != nil {
+			return fmt.Errorf("invalid click_url template: %w", err)
+		}
+	}
+
 	return nil
 }
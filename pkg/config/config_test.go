@@ -21,25 +21,90 @@ func TestDefaultConfig(t *testing.T) {
 	if !cfg.StartupNotify {
 		t.Error("expected StartupNotify to be true by default")
 	}
+	if cfg.NtfyTimeout != 10*time.Second {
+		t.Errorf("expected NtfyTimeout to be 10s but got %v", cfg.NtfyTimeout)
+	}
+}
+
+// envVarNames lists every environment variable read by Load
+var envVarNames = []string{
+	"CLAUDE_NOTIFY_TOPIC",
+	"CLAUDE_NOTIFY_SERVER",
+	"CLAUDE_NOTIFY_DISABLE_KEEPALIVE",
+	"CLAUDE_NOTIFY_PROXY",
+	"CLAUDE_NOTIFY_TIMEOUT",
+	"CLAUDE_NOTIFY_USERNAME",
+	"CLAUDE_NOTIFY_PASSWORD",
+	"CLAUDE_NOTIFY_MARKDOWN",
+	"CLAUDE_NOTIFY_RETRY_ATTEMPTS",
+	"CLAUDE_NOTIFY_RETRY_BASE_DELAY",
+	"CLAUDE_NOTIFY_MESSAGE_PREFIX",
+	"CLAUDE_NOTIFY_MESSAGE_SUFFIX",
+	"CLAUDE_NOTIFY_PRE_HOOK",
+	"CLAUDE_NOTIFY_POST_HOOK",
+	"CLAUDE_NOTIFY_STARTUP_COALESCE_WINDOW",
+	"CLAUDE_NOTIFY_BACKSTOP_TIMEOUT",
+	"CLAUDE_NOTIFY_BACKSTOP_MIN_TIMEOUT",
+	"CLAUDE_NOTIFY_BACKSTOP_DECAY",
+	"CLAUDE_NOTIFY_HEARTBEAT_INTERVAL",
+	"CLAUDE_NOTIFY_DIGEST_INTERVAL",
+	"CLAUDE_NOTIFY_FIRST_OUTPUT_TIMEOUT",
+	"CLAUDE_NOTIFY_HANG_TIMEOUT",
+	"CLAUDE_NOTIFY_MAX_RUNTIME",
+	"CLAUDE_NOTIFY_QUIET",
+	"CLAUDE_NOTIFY_CLAUDE_PATH",
+	"CLAUDE_NOTIFY_DEFAULT_ARGS",
+	"CLAUDE_NOTIFY_STARTUP",
+	"CLAUDE_NOTIFY_STARTUP_INCLUDE_COMMAND",
+	"CLAUDE_NOTIFY_CLICK_URL",
+	"CLAUDE_NOTIFY_INCLUDE_SEQUENCE",
+	"CLAUDE_NOTIFY_INCLUDE_SESSION_ID",
+	"CLAUDE_NOTIFY_STATS_ON_EXIT",
+	"CLAUDE_NOTIFY_EXIT_STATS",
+	"CLAUDE_NOTIFY_ON_EXIT",
+	"CLAUDE_NOTIFY_INTERNAL_ERRORS",
+	"CLAUDE_NOTIFY_SESSION_LOG",
+	"CLAUDE_NOTIFY_LOG_LEVEL",
+	"CLAUDE_NOTIFY_LOG_FILE",
+	"CLAUDE_NOTIFY_FAIL_ON_NOTIFY_ERROR",
+	"CLAUDE_NOTIFY_MESSAGE_FROM_TITLE",
+	"CLAUDE_NOTIFY_MAX_TERMINAL_TITLE_LENGTH",
+	"CLAUDE_NOTIFY_ALLOW_NESTED",
+	"CLAUDE_NOTIFY_TMUX_PANE_AWARE",
+	"CLAUDE_NOTIFY_RESPECT_FRONTMOST_APP",
+	"CLAUDE_NOTIFY_BACKENDS",
+	"CLAUDE_NOTIFY_INCLUDE_GIT_CONTEXT",
+	"CLAUDE_NOTIFY_FALLBACK_BACKEND",
+	"CLAUDE_NOTIFY_MACOS_NOTIFIER",
+	"CLAUDE_NOTIFY_CLICK_FOCUS_TERMINAL",
+	"CLAUDE_NOTIFY_DESKTOP_RETRIES",
+	"CLAUDE_NOTIFY_WEBHOOK_URL",
+	"CLAUDE_NOTIFY_WEBHOOK_METHOD",
+	"CLAUDE_NOTIFY_WEBHOOK_HEADERS",
+	"CLAUDE_NOTIFY_WEBHOOK_BODY_TEMPLATE",
+	"CLAUDE_NOTIFY_SUPPRESS_UNTIL_PROMPT",
+	"CLAUDE_NOTIFY_CONFIG",
+	"CLAUDE_NOTIFY_IN_CI",
+	// CI detection
+	"CI",
+	"GITHUB_ACTIONS",
+	"GITLAB_CI",
+	"BUILDKITE",
+	"CIRCLECI",
+	"JENKINS_URL",
+	"TF_BUILD",
 }
 
 func TestLoadFromEnv(t *testing.T) {
 	// Save original env and restore after test
-	origTopic := os.Getenv("CLAUDE_NOTIFY_TOPIC")
-	origServer := os.Getenv("CLAUDE_NOTIFY_SERVER")
-	origBackstopTimeout := os.Getenv("CLAUDE_NOTIFY_BACKSTOP_TIMEOUT")
-	origQuiet := os.Getenv("CLAUDE_NOTIFY_QUIET")
-	origClaudePath := os.Getenv("CLAUDE_NOTIFY_CLAUDE_PATH")
-	origDefaultArgs := os.Getenv("CLAUDE_NOTIFY_DEFAULT_ARGS")
-	origStartup := os.Getenv("CLAUDE_NOTIFY_STARTUP")
+	origEnv := make(map[string]string, len(envVarNames))
+	for _, name := range envVarNames {
+		origEnv[name] = os.Getenv(name)
+	}
 	defer func() {
-		_ = os.Setenv("CLAUDE_NOTIFY_TOPIC", origTopic)
-		_ = os.Setenv("CLAUDE_NOTIFY_SERVER", origServer)
-		_ = os.Setenv("CLAUDE_NOTIFY_BACKSTOP_TIMEOUT", origBackstopTimeout)
-		_ = os.Setenv("CLAUDE_NOTIFY_QUIET", origQuiet)
-		_ = os.Setenv("CLAUDE_NOTIFY_CLAUDE_PATH", origClaudePath)
-		_ = os.Setenv("CLAUDE_NOTIFY_DEFAULT_ARGS", origDefaultArgs)
-		_ = os.Setenv("CLAUDE_NOTIFY_STARTUP", origStartup)
+		for name, value := range origEnv {
+			_ = os.Setenv(name, value)
+		}
 	}()
 
 	tests := []struct {
@@ -82,6 +147,110 @@ func TestLoadFromEnv(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "message prefix and suffix",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":          "test-topic",
+				"CLAUDE_NOTIFY_MESSAGE_PREFIX": "[dev] ",
+				"CLAUDE_NOTIFY_MESSAGE_SUFFIX": " (laptop)",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if cfg.MessagePrefix != "[dev] " {
+					t.Errorf("expected MessagePrefix to be %q but got %q", "[dev] ", cfg.MessagePrefix)
+				}
+				if cfg.MessageSuffix != " (laptop)" {
+					t.Errorf("expected MessageSuffix to be %q but got %q", " (laptop)", cfg.MessageSuffix)
+				}
+			},
+		},
+		{
+			name: "hooks",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":     "test-topic",
+				"CLAUDE_NOTIFY_PRE_HOOK":  "jq .",
+				"CLAUDE_NOTIFY_POST_HOOK": "logger -t claude",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if cfg.PreHook != "jq ." {
+					t.Errorf("expected PreHook to be set but got %q", cfg.PreHook)
+				}
+				if cfg.PostHook != "logger -t claude" {
+					t.Errorf("expected PostHook to be set but got %q", cfg.PostHook)
+				}
+			},
+		},
+		{
+			name: "heartbeat interval",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":              "test-topic",
+				"CLAUDE_NOTIFY_HEARTBEAT_INTERVAL": "1h",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if cfg.HeartbeatInterval != time.Hour {
+					t.Errorf("expected HeartbeatInterval to be 1h but got %v", cfg.HeartbeatInterval)
+				}
+			},
+		},
+		{
+			name: "digest interval",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":           "test-topic",
+				"CLAUDE_NOTIFY_DIGEST_INTERVAL": "15m",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if cfg.DigestInterval != 15*time.Minute {
+					t.Errorf("expected DigestInterval to be 15m but got %v", cfg.DigestInterval)
+				}
+			},
+		},
+		{
+			name: "first output timeout",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":                "test-topic",
+				"CLAUDE_NOTIFY_FIRST_OUTPUT_TIMEOUT": "1m",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if cfg.FirstOutputTimeout != time.Minute {
+					t.Errorf("expected FirstOutputTimeout to be 1m but got %v", cfg.FirstOutputTimeout)
+				}
+			},
+		},
+		{
+			name: "startup include command",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":                   "test-topic",
+				"CLAUDE_NOTIFY_STARTUP_INCLUDE_COMMAND": "true",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if !cfg.StartupIncludeCommand {
+					t.Error("expected StartupIncludeCommand to be true")
+				}
+			},
+		},
+		{
+			name: "max runtime",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":       "test-topic",
+				"CLAUDE_NOTIFY_MAX_RUNTIME": "2h",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if cfg.MaxRuntime != 2*time.Hour {
+					t.Errorf("expected MaxRuntime to be 2h but got %v", cfg.MaxRuntime)
+				}
+			},
+		},
+		{
+			name: "hang timeout",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":        "test-topic",
+				"CLAUDE_NOTIFY_HANG_TIMEOUT": "10m",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if cfg.HangTimeout != 10*time.Minute {
+					t.Errorf("expected HangTimeout to be 10m but got %v", cfg.HangTimeout)
+				}
+			},
+		},
 		{
 			name: "invalid quiet value",
 			envVars: map[string]string{
@@ -160,6 +329,491 @@ func TestLoadFromEnv(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "startup coalesce window",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":                   "test-topic",
+				"CLAUDE_NOTIFY_STARTUP_COALESCE_WINDOW": "5s",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if cfg.StartupCoalesceWindow != 5*time.Second {
+					t.Errorf("expected StartupCoalesceWindow to be 5s but got %v", cfg.StartupCoalesceWindow)
+				}
+			},
+		},
+		{
+			name: "allow nested",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":        "test-topic",
+				"CLAUDE_NOTIFY_ALLOW_NESTED": "1",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if !cfg.AllowNested {
+					t.Error("expected AllowNested to be true")
+				}
+			},
+		},
+		{
+			name: "retry settings",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":            "test-topic",
+				"CLAUDE_NOTIFY_RETRY_ATTEMPTS":   "3",
+				"CLAUDE_NOTIFY_RETRY_BASE_DELAY": "500ms",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if cfg.RetryAttempts != 3 {
+					t.Errorf("expected RetryAttempts to be 3 but got %d", cfg.RetryAttempts)
+				}
+				if cfg.RetryBaseDelay != 500*time.Millisecond {
+					t.Errorf("expected RetryBaseDelay to be 500ms but got %v", cfg.RetryBaseDelay)
+				}
+			},
+		},
+		{
+			name: "invalid retry attempts",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":          "test-topic",
+				"CLAUDE_NOTIFY_RETRY_ATTEMPTS": "lots",
+			},
+			wantErr: true,
+		},
+		{
+			name: "markdown",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":    "test-topic",
+				"CLAUDE_NOTIFY_MARKDOWN": "auto",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if cfg.Markdown != "auto" {
+					t.Errorf("expected Markdown to be auto but got %q", cfg.Markdown)
+				}
+			},
+		},
+		{
+			name: "invalid markdown mode",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":    "test-topic",
+				"CLAUDE_NOTIFY_MARKDOWN": "sometimes",
+			},
+			wantErr: true,
+		},
+		{
+			name: "basic auth",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":    "test-topic",
+				"CLAUDE_NOTIFY_USERNAME": "phil",
+				"CLAUDE_NOTIFY_PASSWORD": "secret",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if cfg.NtfyUsername != "phil" || cfg.NtfyPassword != "secret" {
+					t.Errorf("expected basic auth credentials but got %q/%q", cfg.NtfyUsername, cfg.NtfyPassword)
+				}
+			},
+		},
+		{
+			name: "username without password",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":    "test-topic",
+				"CLAUDE_NOTIFY_USERNAME": "phil",
+			},
+			wantErr: true,
+		},
+		{
+			name: "ntfy timeout",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":   "test-topic",
+				"CLAUDE_NOTIFY_TIMEOUT": "3s",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if cfg.NtfyTimeout != 3*time.Second {
+					t.Errorf("expected NtfyTimeout to be 3s but got %v", cfg.NtfyTimeout)
+				}
+			},
+		},
+		{
+			name: "proxy",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC": "test-topic",
+				"CLAUDE_NOTIFY_PROXY": "http://proxy.example.com:8080",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if cfg.NtfyProxy != "http://proxy.example.com:8080" {
+					t.Errorf("expected NtfyProxy to be set but got %q", cfg.NtfyProxy)
+				}
+			},
+		},
+		{
+			name: "invalid proxy",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC": "test-topic",
+				"CLAUDE_NOTIFY_PROXY": "proxy.example.com",
+			},
+			wantErr: true,
+		},
+		{
+			name: "disable keepalive",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":             "test-topic",
+				"CLAUDE_NOTIFY_DISABLE_KEEPALIVE": "true",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if !cfg.NtfyDisableKeepAlive {
+					t.Error("expected NtfyDisableKeepAlive to be true")
+				}
+			},
+		},
+		{
+			name: "message from title",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":              "test-topic",
+				"CLAUDE_NOTIFY_MESSAGE_FROM_TITLE": "true",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if !cfg.MessageFromTitle {
+					t.Error("expected MessageFromTitle to be true")
+				}
+			},
+		},
+		{
+			name: "max terminal title length",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":                     "test-topic",
+				"CLAUDE_NOTIFY_MAX_TERMINAL_TITLE_LENGTH": "40",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if cfg.MaxTerminalTitleLength != 40 {
+					t.Errorf("expected MaxTerminalTitleLength to be 40, got %d", cfg.MaxTerminalTitleLength)
+				}
+			},
+		},
+		{
+			name: "fail on notify error",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":                "test-topic",
+				"CLAUDE_NOTIFY_FAIL_ON_NOTIFY_ERROR": "true",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if !cfg.FailOnNotifyError {
+					t.Error("expected FailOnNotifyError to be true")
+				}
+			},
+		},
+		{
+			name: "stats on exit",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":         "test-topic",
+				"CLAUDE_NOTIFY_STATS_ON_EXIT": "true",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if !cfg.StatsOnExit {
+					t.Error("expected StatsOnExit to be true")
+				}
+			},
+		},
+		{
+			name: "exit stats",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":      "test-topic",
+				"CLAUDE_NOTIFY_EXIT_STATS": "true",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if !cfg.ExitStats {
+					t.Error("expected ExitStats to be true")
+				}
+			},
+		},
+		{
+			name: "notify on exit",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":   "test-topic",
+				"CLAUDE_NOTIFY_ON_EXIT": "true",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if !cfg.NotifyOnExit {
+					t.Error("expected NotifyOnExit to be true")
+				}
+			},
+		},
+		{
+			name: "notify internal errors",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":           "test-topic",
+				"CLAUDE_NOTIFY_INTERNAL_ERRORS": "true",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if !cfg.NotifyInternalErrors {
+					t.Error("expected NotifyInternalErrors to be true")
+				}
+			},
+		},
+		{
+			name: "log level and file",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":     "test-topic",
+				"CLAUDE_NOTIFY_LOG_LEVEL": "info",
+				"CLAUDE_NOTIFY_LOG_FILE":  "/tmp/claude-code-ntfy.log",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if cfg.LogLevel != "info" {
+					t.Errorf("expected LogLevel to be info, got %q", cfg.LogLevel)
+				}
+				if cfg.LogFile != "/tmp/claude-code-ntfy.log" {
+					t.Errorf("expected LogFile to be /tmp/claude-code-ntfy.log, got %q", cfg.LogFile)
+				}
+			},
+		},
+		{
+			name: "session log",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":       "test-topic",
+				"CLAUDE_NOTIFY_SESSION_LOG": "/tmp/claude-session.log",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if cfg.SessionLog != "/tmp/claude-session.log" {
+					t.Errorf("expected SessionLog to be /tmp/claude-session.log, got %q", cfg.SessionLog)
+				}
+			},
+		},
+		{
+			name: "click url",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":     "test-topic",
+				"CLAUDE_NOTIFY_CLICK_URL": "https://ci.example.com/{{.Pattern}}",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if cfg.ClickURL != "https://ci.example.com/{{.Pattern}}" {
+					t.Errorf("expected ClickURL to be set but got %q", cfg.ClickURL)
+				}
+			},
+		},
+		{
+			name: "include session id",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":              "test-topic",
+				"CLAUDE_NOTIFY_INCLUDE_SESSION_ID": "true",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if !cfg.IncludeSessionID {
+					t.Error("expected IncludeSessionID to be true")
+				}
+			},
+		},
+		{
+			name: "include sequence",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":            "test-topic",
+				"CLAUDE_NOTIFY_INCLUDE_SEQUENCE": "true",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if !cfg.IncludeSequence {
+					t.Error("expected IncludeSequence to be true")
+				}
+			},
+		},
+		{
+			name: "suppress until prompt",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":                 "test-topic",
+				"CLAUDE_NOTIFY_SUPPRESS_UNTIL_PROMPT": "true",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if !cfg.SuppressUntilPrompt {
+					t.Error("expected SuppressUntilPrompt to be true")
+				}
+			},
+		},
+		{
+			name: "tmux pane aware",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":           "test-topic",
+				"CLAUDE_NOTIFY_TMUX_PANE_AWARE": "true",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if !cfg.TmuxPaneAware {
+					t.Error("expected TmuxPaneAware to be true")
+				}
+			},
+		},
+		{
+			name: "backends",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":    "test-topic",
+				"CLAUDE_NOTIFY_BACKENDS": "ntfy, stdout",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if strings.Join(cfg.Backends, ",") != "ntfy,stdout" {
+					t.Errorf("expected backends [ntfy stdout], got %v", cfg.Backends)
+				}
+			},
+		},
+		{
+			name: "webhook",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_BACKENDS":              "webhook",
+				"CLAUDE_NOTIFY_WEBHOOK_URL":           "https://hooks.example.com/claude",
+				"CLAUDE_NOTIFY_WEBHOOK_METHOD":        "PUT",
+				"CLAUDE_NOTIFY_WEBHOOK_HEADERS":       "Authorization=Bearer abc, X-Source=claude",
+				"CLAUDE_NOTIFY_WEBHOOK_BODY_TEMPLATE": `{"text": {{json .Message}}}`,
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if cfg.WebhookURL != "https://hooks.example.com/claude" || cfg.WebhookMethod != "PUT" {
+					t.Errorf("unexpected webhook url/method: %q %q", cfg.WebhookURL, cfg.WebhookMethod)
+				}
+				if cfg.WebhookHeaders["Authorization"] != "Bearer abc" || cfg.WebhookHeaders["X-Source"] != "claude" {
+					t.Errorf("unexpected webhook headers: %v", cfg.WebhookHeaders)
+				}
+				if cfg.WebhookBodyTemplate != `{"text": {{json .Message}}}` {
+					t.Errorf("unexpected webhook body template: %q", cfg.WebhookBodyTemplate)
+				}
+			},
+		},
+		{
+			name: "backstop decay",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":                "test-topic",
+				"CLAUDE_NOTIFY_BACKSTOP_TIMEOUT":     "5m",
+				"CLAUDE_NOTIFY_BACKSTOP_MIN_TIMEOUT": "1m",
+				"CLAUDE_NOTIFY_BACKSTOP_DECAY":       "1h",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if cfg.BackstopMinTimeout != time.Minute || cfg.BackstopDecay != time.Hour {
+					t.Errorf("expected 1m decaying over 1h, got %v over %v", cfg.BackstopMinTimeout, cfg.BackstopDecay)
+				}
+			},
+		},
+		{
+			name: "include git context",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":               "test-topic",
+				"CLAUDE_NOTIFY_INCLUDE_GIT_CONTEXT": "true",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if !cfg.IncludeGitContext {
+					t.Error("expected IncludeGitContext to be true")
+				}
+			},
+		},
+		{
+			name: "fallback backend",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":            "test-topic",
+				"CLAUDE_NOTIFY_FALLBACK_BACKEND": "desktop",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if cfg.FallbackBackend != "desktop" {
+					t.Errorf("expected fallback backend desktop, got %q", cfg.FallbackBackend)
+				}
+			},
+		},
+		{
+			name: "macos notifier",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":          "test-topic",
+				"CLAUDE_NOTIFY_MACOS_NOTIFIER": "osascript",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if cfg.MacOSNotifier != "osascript" {
+					t.Errorf("expected macos notifier osascript, got %q", cfg.MacOSNotifier)
+				}
+			},
+		},
+		{
+			name: "desktop retries",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":           "test-topic",
+				"CLAUDE_NOTIFY_DESKTOP_RETRIES": "2",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if cfg.DesktopRetries != 2 {
+					t.Errorf("expected DesktopRetries to be 2 but got %d", cfg.DesktopRetries)
+				}
+			},
+		},
+		{
+			name: "respect frontmost app",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":                 "test-topic",
+				"CLAUDE_NOTIFY_RESPECT_FRONTMOST_APP": "true",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if !cfg.RespectFrontmostApp {
+					t.Error("expected RespectFrontmostApp to be true")
+				}
+			},
+		},
+		{
+			name: "click focus terminal",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":                "test-topic",
+				"CLAUDE_NOTIFY_CLICK_FOCUS_TERMINAL": "true",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if !cfg.ClickFocusTerminal {
+					t.Error("expected ClickFocusTerminal to be true")
+				}
+			},
+		},
+		{
+			name: "invalid webhook header",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_TOPIC":           "test-topic",
+				"CLAUDE_NOTIFY_WEBHOOK_HEADERS": "Authorization",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid allow nested value",
+			envVars: map[string]string{
+				"CLAUDE_NOTIFY_ALLOW_NESTED": "sometimes",
+			},
+			wantErr: true,
+		},
+		{
+			name: "CI only logs notifications",
+			envVars: map[string]string{
+				"CI":                             "true",
+				"CLAUDE_NOTIFY_BACKENDS":         "ntfy,desktop",
+				"CLAUDE_NOTIFY_FALLBACK_BACKEND": "desktop",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if strings.Join(cfg.Backends, ",") != "stdout" || cfg.FallbackBackend != "" {
+					t.Errorf("expected only the stdout backend in CI, got %v and fallback %q", cfg.Backends, cfg.FallbackBackend)
+				}
+			},
+		},
+		{
+			name: "notify in CI",
+			envVars: map[string]string{
+				"GITHUB_ACTIONS":      "true",
+				"CLAUDE_NOTIFY_TOPIC": "test-topic",
+				"CLAUDE_NOTIFY_IN_CI": "true",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if !cfg.NotifyInCI || len(cfg.Backends) != 0 {
+					t.Errorf("expected the configured backends in CI, got %v", cfg.Backends)
+				}
+			},
+		},
+		{
+			name: "notify in CI still needs a topic",
+			envVars: map[string]string{
+				"CI":                  "1",
+				"CLAUDE_NOTIFY_IN_CI": "true",
+			},
+			wantErr: true,
+		},
+		{
+			name: "CI disabled",
+			envVars: map[string]string{
+				"CI":                  "false",
+				"CLAUDE_NOTIFY_TOPIC": "test-topic",
+			},
+			checkFunc: func(t *testing.T, cfg *Config) {
+				if len(cfg.Backends) != 0 {
+					t.Errorf("expected the default backends outside CI, got %v", cfg.Backends)
+				}
+			},
+		},
 		{
 			name: "invalid startup value",
 			envVars: map[string]string{
@@ -172,14 +826,9 @@ func TestLoadFromEnv(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Clear all env vars first
-			_ = os.Unsetenv("CLAUDE_NOTIFY_TOPIC")
-			_ = os.Unsetenv("CLAUDE_NOTIFY_SERVER")
-			_ = os.Unsetenv("CLAUDE_NOTIFY_BACKSTOP_TIMEOUT")
-			_ = os.Unsetenv("CLAUDE_NOTIFY_QUIET")
-			_ = os.Unsetenv("CLAUDE_NOTIFY_CLAUDE_PATH")
-			_ = os.Unsetenv("CLAUDE_NOTIFY_DEFAULT_ARGS")
-			_ = os.Unsetenv("CLAUDE_NOTIFY_STARTUP")
-			_ = os.Unsetenv("CLAUDE_NOTIFY_CONFIG")
+			for _, name := range envVarNames {
+				_ = os.Unsetenv(name)
+			}
 
 			// Set test env vars
 			for k, v := range tt.envVars {
@@ -335,6 +984,111 @@ func TestValidate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "missing topic allowed without ntfy backend",
+			cfg: &Config{
+				Backends: []string{"stdout"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown backend",
+			cfg: &Config{
+				NtfyTopic: "test",
+				Backends:  []string{"ntfy", "pigeon"},
+			},
+			wantErr:  true,
+			errorMsg: "invalid backend",
+		},
+		{
+			name: "backstop min timeout above timeout",
+			cfg: &Config{
+				NtfyTopic:          "test",
+				BackstopTimeout:    time.Minute,
+				BackstopMinTimeout: 5 * time.Minute,
+			},
+			wantErr:  true,
+			errorMsg: "backstop_min_timeout must not exceed backstop_timeout",
+		},
+		{
+			name: "unknown fallback backend",
+			cfg: &Config{
+				NtfyTopic:       "test",
+				FallbackBackend: "pigeon",
+			},
+			wantErr:  true,
+			errorMsg: "invalid fallback_backend",
+		},
+		{
+			name: "valid macos notifier",
+			cfg: &Config{
+				NtfyTopic:     "test",
+				MacOSNotifier: "terminal-notifier",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid macos notifier",
+			cfg: &Config{
+				NtfyTopic:     "test",
+				MacOSNotifier: "growl",
+			},
+			wantErr:  true,
+			errorMsg: "macos_notifier must be one of",
+		},
+		{
+			name: "negative max terminal title length",
+			cfg: &Config{
+				NtfyTopic:              "test",
+				MaxTerminalTitleLength: -1,
+			},
+			wantErr:  true,
+			errorMsg: "max_terminal_title_length must be non-negative",
+		},
+		{
+			name: "valid log level",
+			cfg: &Config{
+				NtfyTopic: "test",
+				LogLevel:  "debug",
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid log level",
+			cfg: &Config{
+				NtfyTopic: "test",
+				LogLevel:  "verbose",
+			},
+			wantErr:  true,
+			errorMsg: "log_level must be one of",
+		},
+		{
+			name: "webhook fallback without url",
+			cfg: &Config{
+				NtfyTopic:       "test",
+				FallbackBackend: "webhook",
+			},
+			wantErr:  true,
+			errorMsg: "webhook_url must be a URL",
+		},
+		{
+			name: "webhook backend without url",
+			cfg: &Config{
+				Backends: []string{"webhook"},
+			},
+			wantErr:  true,
+			errorMsg: "webhook_url must be a URL",
+		},
+		{
+			name: "invalid webhook body template",
+			cfg: &Config{
+				Backends:            []string{"webhook"},
+				WebhookURL:          "https://hooks.example.com/claude",
+				WebhookBodyTemplate: `{"text": {{json .Message}`,
+			},
+			wantErr:  true,
+			errorMsg: "invalid webhook_body_template",
+		},
 		{
 			name: "negative backstop timeout",
 			cfg: &Config{
@@ -344,6 +1098,60 @@ func TestValidate(t *testing.T) {
 			wantErr:  true,
 			errorMsg: "must be non-negative",
 		},
+		{
+			name: "invalid click url template",
+			cfg: &Config{
+				NtfyTopic: "test",
+				ClickURL:  "https://ci.example.com/{{.Pattern",
+			},
+			wantErr:  true,
+			errorMsg: "invalid click_url template",
+		},
+		{
+			name: "negative retry attempts",
+			cfg: &Config{
+				NtfyTopic:     "test",
+				RetryAttempts: -1,
+			},
+			wantErr:  true,
+			errorMsg: "retry_attempts must be non-negative",
+		},
+		{
+			name: "too many retry attempts",
+			cfg: &Config{
+				NtfyTopic:     "test",
+				RetryAttempts: 11,
+			},
+			wantErr:  true,
+			errorMsg: "retry_attempts must be at most 10",
+		},
+		{
+			name: "negative desktop retries",
+			cfg: &Config{
+				NtfyTopic:      "test",
+				DesktopRetries: -1,
+			},
+			wantErr:  true,
+			errorMsg: "desktop_retries must be non-negative",
+		},
+		{
+			name: "negative heartbeat interval",
+			cfg: &Config{
+				NtfyTopic:         "test",
+				HeartbeatInterval: -1 * time.Minute,
+			},
+			wantErr:  true,
+			errorMsg: "heartbeat_interval must be non-negative",
+		},
+		{
+			name: "negative digest interval",
+			cfg: &Config{
+				NtfyTopic:      "test",
+				DigestInterval: -1 * time.Minute,
+			},
+			wantErr:  true,
+			errorMsg: "digest_interval must be non-negative",
+		},
 	}
 
 	for _, tt := range tests {
@@ -421,7 +1229,45 @@ func TestGetConfigPath(t *testing.T) {
 	}
 }
 
+func TestSnoozePath(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "/xdg/state")
+	if path := SnoozePath(); path != "/xdg/state/claude-code-ntfy/snooze" {
+		t.Errorf("expected XDG snooze path but got %q", path)
+	}
+
+	t.Setenv("XDG_STATE_HOME", "")
+	if path := SnoozePath(); !contains(path, ".local/state/claude-code-ntfy/snooze") {
+		t.Errorf("expected home snooze path but got %q", path)
+	}
+}
+
 // Helper function
 func contains(s, substr string) bool {
 	return strings.Contains(s, substr)
 }
+
+func TestIsCI(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{name: "not CI", env: map[string]string{}, want: false},
+		{name: "CI true", env: map[string]string{"CI": "true"}, want: true},
+		{name: "CI false", env: map[string]string{"CI": "false"}, want: false},
+		{name: "CI 0", env: map[string]string{"CI": "0"}, want: false},
+		{name: "Jenkins", env: map[string]string{"JENKINS_URL": "https://jenkins.example.com/"}, want: true},
+		{name: "GitLab", env: map[string]string{"GITLAB_CI": "true"}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range ciEnvVars {
+				t.Setenv(name, tt.env[name])
+			}
+			if got := IsCI(); got != tt.want {
+				t.Errorf("IsCI() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
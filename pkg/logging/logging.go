@@ -0,0 +1,85 @@
+// Package logging sets up the leveled log claude-code-ntfy writes its debug output and warnings to
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+)
+
+// Level names accepted for log_level
+const (
+	LevelDebug = "debug"
+	LevelInfo  = "info"
+	LevelWarn  = "warn"
+	LevelError = "error"
+)
+
+// ParseLevel converts a log_level name to a slog level.
+// An empty name means warn, so only problems are logged by default.
+func ParseLevel(name string) (slog.Level, error) {
+	switch name {
+	case LevelDebug:
+		return slog.LevelDebug, nil
+	case LevelInfo:
+		return slog.LevelInfo, nil
+	case "", LevelWarn:
+		return slog.LevelWarn, nil
+	case LevelError:
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("unknown log level %q", name)
+	}
+}
+
+// New creates a logger writing records at level or above to w as text.
+// Records for a terminal leave out the time and start with our name, so they stand out among Claude's output.
+func New(w io.Writer, level slog.Level, terminal bool) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level}
+	if terminal {
+		w = &prefixWriter{w: w}
+		opts.ReplaceAttr = func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		}
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
+}
+
+// Setup makes the default logger write to the file at path, or to stderr if path is empty.
+// The file is opened in append mode; the returned function closes it.
+func Setup(path, levelName string) (func() error, error) {
+	level, err := ParseLevel(levelName)
+	if err != nil {
+		return nil, err
+	}
+
+	if path == "" {
+		slog.SetDefault(New(os.Stderr, level, true))
+		return func() error { return nil }, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+	slog.SetDefault(New(file, level, false))
+	return file.Close, nil
+}
+
+// prefixWriter starts every record with our name.
+// The text handler writes each record with a single Write.
+type prefixWriter struct {
+	w io.Writer
+}
+
+// Write implements io.Writer
+func (pw *prefixWriter) Write(p []byte) (int, error) {
+	if _, err := pw.w.Write(append([]byte("claude-code-ntfy: "), p...)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
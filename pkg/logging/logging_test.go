@@ -0,0 +1,110 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    slog.Level
+		wantErr bool
+	}{
+		{name: "", want: slog.LevelWarn},
+		{name: "debug", want: slog.LevelDebug},
+		{name: "info", want: slog.LevelInfo},
+		{name: "warn", want: slog.LevelWarn},
+		{name: "error", want: slog.LevelError},
+		{name: "verbose", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLevel(tt.name)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error for %q", tt.name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLevel(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		terminal bool
+		want     string
+		wantTime bool
+	}{
+		{
+			name:     "terminal",
+			terminal: true,
+			want:     "claude-code-ntfy: level=WARN msg=\"failed to resize PTY\" err=gone\n",
+		},
+		{
+			name:     "file",
+			terminal: false,
+			want:     "level=WARN msg=\"failed to resize PTY\" err=gone\n",
+			wantTime: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := New(&buf, slog.LevelWarn, tt.terminal)
+			logger.Debug("bell detected")
+			logger.Warn("failed to resize PTY", "err", "gone")
+
+			got := buf.String()
+			if !strings.HasSuffix(got, tt.want) {
+				t.Errorf("expected record %q, got %q", tt.want, got)
+			}
+			if strings.Contains(got, "time=") != tt.wantTime {
+				t.Errorf("expected time in record to be %v, got %q", tt.wantTime, got)
+			}
+			if strings.Contains(got, "bell detected") {
+				t.Errorf("expected debug record to be filtered out, got %q", got)
+			}
+		})
+	}
+}
+
+func TestSetup(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+
+	path := filepath.Join(t.TempDir(), "claude-code-ntfy.log")
+	closeLog, err := Setup(path, "debug")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	slog.Debug("bell detected")
+	if err := closeLog(); err != nil {
+		t.Fatalf("failed to close log: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log: %v", err)
+	}
+	if !strings.Contains(string(data), `level=DEBUG msg="bell detected"`) {
+		t.Errorf("expected debug record in log file, got %q", data)
+	}
+
+	if _, err := Setup(path, "verbose"); err == nil {
+		t.Error("expected error for an unknown level")
+	}
+}
@@ -3,7 +3,7 @@ package monitor
 import (
 	"bytes"
 	"fmt"
-	"os"
+	"log/slog"
 	"sync"
 	"time"
 
@@ -16,11 +16,22 @@ import (
 type OutputMonitor struct {
 	config   *config.Config
 	notifier notification.Notifier
+	// alertNotifier sends the monitor's own alerts, below the backstop so they don't count as activity
+	alertNotifier notification.Notifier
 
 	mu             sync.Mutex
 	lastOutputTime time.Time
 	lineBuffer     bytes.Buffer
 
+	// First output watchdog
+	firstOutputTimer *time.Timer
+	outputReceived   bool
+
+	// Hang detection - output that only rewrites the current line (a spinner)
+	lastLineTime time.Time
+	hangNotified bool
+	now          func() time.Time
+
 	// Terminal sequence detection
 	sequenceDetector   interfaces.TerminalSequenceDetector
 	screenEventHandler interfaces.ScreenEventHandler
@@ -33,15 +44,52 @@ func NewOutputMonitor(cfg *config.Config, notifier notification.Notifier) *Outpu
 	om := &OutputMonitor{
 		config:           cfg,
 		notifier:         notifier,
+		alertNotifier:    notifier,
 		lastOutputTime:   now,
+		lastLineTime:     now,
+		now:              time.Now,
 		sequenceDetector: NewTerminalSequenceDetector(),
 		terminalState:    NewTerminalState(),
 	}
 	// Set self as the screen event handler
 	om.screenEventHandler = om
+
 	return om
 }
 
+// HandleStart starts the first output watchdog once Claude has been started
+func (om *OutputMonitor) HandleStart() {
+	om.mu.Lock()
+	defer om.mu.Unlock()
+
+	if om.config == nil || om.config.FirstOutputTimeout <= 0 || om.outputReceived || om.firstOutputTimer != nil {
+		return
+	}
+	om.firstOutputTimer = time.AfterFunc(om.config.FirstOutputTimeout, om.sendFirstOutputNotification)
+}
+
+// sendFirstOutputNotification notifies that Claude never produced any output
+func (om *OutputMonitor) sendFirstOutputNotification() {
+	om.mu.Lock()
+	if om.outputReceived {
+		om.mu.Unlock()
+		return
+	}
+	notifier := om.alertNotifier
+	om.mu.Unlock()
+
+	if notifier == nil {
+		return
+	}
+
+	_ = notifier.Send(notification.Notification{
+		Title:   "Claude hasn't started",
+		Message: fmt.Sprintf("No output received within %s of starting", om.config.FirstOutputTimeout),
+		Time:    time.Now(),
+		Pattern: "first_output",
+	})
+}
+
 // SetScreenEventHandler sets the handler for screen events
 func (om *OutputMonitor) SetScreenEventHandler(handler interfaces.ScreenEventHandler) {
 	om.mu.Lock()
@@ -56,6 +104,13 @@ func (om *OutputMonitor) SetNotifier(notifier notification.Notifier) {
 	om.notifier = notifier
 }
 
+// SetAlertNotifier sets the notifier for the first output and hang alerts
+func (om *OutputMonitor) SetAlertNotifier(notifier notification.Notifier) {
+	om.mu.Lock()
+	defer om.mu.Unlock()
+	om.alertNotifier = notifier
+}
+
 // containsVisibleContent checks if the data contains any visible characters
 // Visible characters include printable ASCII, newlines, tabs, and Unicode text
 // Returns false for data containing only ANSI escape sequences or control characters
@@ -149,11 +204,22 @@ func (om *OutputMonitor) HandleData(data []byte) {
 
 	// Mark activity for backstop timer only if visible content is detected
 	if containsVisibleContent(data) {
+		// Claude has started, so cancel the first output watchdog. Escape
+		// sequences alone, like a terminal reset, don't count.
+		if !om.outputReceived {
+			om.outputReceived = true
+			if om.firstOutputTimer != nil {
+				om.firstOutputTimer.Stop()
+			}
+		}
+
 		if marker, ok := om.notifier.(notification.ActivityMarker); ok {
 			marker.MarkActivity()
 		}
 	}
 
+	om.checkForHang(data)
+
 	// Add data to line buffer for processing
 	om.lineBuffer.Write(data)
 
@@ -177,6 +243,45 @@ func (om *OutputMonitor) HandleData(data []byte) {
 	}
 }
 
+// checkForHang notifies once when output has only been rewriting the current line with \r,
+// like a spinner, for longer than the hang timeout. A completed line counts as progress.
+// Must be called with om.mu held
+func (om *OutputMonitor) checkForHang(data []byte) {
+	if om.config == nil || om.config.HangTimeout <= 0 {
+		return
+	}
+
+	now := om.now()
+	if bytes.IndexByte(data, '\n') >= 0 {
+		om.lastLineTime = now
+		om.hangNotified = false
+		return
+	}
+
+	if bytes.IndexByte(data, '\r') < 0 || om.hangNotified || now.Sub(om.lastLineTime) < om.config.HangTimeout {
+		return
+	}
+
+	om.hangNotified = true
+	// Read under om.mu, which the caller holds, like SetAlertNotifier writes it
+	notifier := om.alertNotifier
+	if notifier == nil {
+		return
+	}
+
+	slog.Debug("only spinner output, possible hang", "for", om.config.HangTimeout)
+
+	// Send without holding up the output path
+	go func() {
+		_ = notifier.Send(notification.Notification{
+			Title:   "Claude may be stuck",
+			Message: fmt.Sprintf("Only spinner output for %s", om.config.HangTimeout),
+			Time:    now,
+			Pattern: "hang",
+		})
+	}()
+}
+
 // processLine checks for bell character
 func (om *OutputMonitor) processLine(line []byte) {
 	// Check for bell character
@@ -184,9 +289,7 @@ func (om *OutputMonitor) processLine(line []byte) {
 		// Bell detected, disable backstop timer
 		if backstopSetter, ok := om.notifier.(interface{ SetBackstopSent(bool) }); ok {
 			backstopSetter.SetBackstopSent(true)
-			if os.Getenv("CLAUDE_NOTIFY_DEBUG") == "true" {
-				fmt.Fprintf(os.Stderr, "claude-code-ntfy: bell detected, disabling backstop timer\n")
-			}
+			slog.Debug("bell detected, disabling backstop timer")
 		}
 	}
 }
@@ -204,6 +307,16 @@ func (om *OutputMonitor) Flush() {
 	}
 }
 
+// Close stops any pending monitor timers
+func (om *OutputMonitor) Close() {
+	om.mu.Lock()
+	defer om.mu.Unlock()
+
+	if om.firstOutputTimer != nil {
+		om.firstOutputTimer.Stop()
+	}
+}
+
 // HandleLine implements the OutputHandler interface
 func (om *OutputMonitor) HandleLine(line string) {
 	om.HandleData([]byte(line + "\n"))
@@ -223,33 +336,25 @@ func (om *OutputMonitor) HandleScreenClear() {
 		resetter.ResetSession()
 	}
 
-	if os.Getenv("CLAUDE_NOTIFY_DEBUG") == "true" {
-		fmt.Fprintf(os.Stderr, "claude-code-ntfy: screen cleared - resetting session\n")
-	}
+	slog.Debug("screen cleared, resetting session")
 }
 
 // HandleTitleChange implements ScreenEventHandler
 func (om *OutputMonitor) HandleTitleChange(title string) {
 	om.terminalState.SetTitle(title)
-	if os.Getenv("CLAUDE_NOTIFY_DEBUG") == "true" {
-		fmt.Fprintf(os.Stderr, "claude-code-ntfy: terminal title changed to: %q\n", title)
-	}
+	slog.Debug("terminal title changed", "title", title)
 }
 
 // HandleFocusIn implements ScreenEventHandler
 func (om *OutputMonitor) HandleFocusIn() {
 	om.terminalState.SetFocused(true)
-	if os.Getenv("CLAUDE_NOTIFY_DEBUG") == "true" {
-		fmt.Fprintf(os.Stderr, "claude-code-ntfy: terminal gained focus\n")
-	}
+	slog.Debug("terminal gained focus")
 }
 
 // HandleFocusOut implements ScreenEventHandler
 func (om *OutputMonitor) HandleFocusOut() {
 	om.terminalState.SetFocused(false)
-	if os.Getenv("CLAUDE_NOTIFY_DEBUG") == "true" {
-		fmt.Fprintf(os.Stderr, "claude-code-ntfy: terminal lost focus\n")
-	}
+	slog.Debug("terminal lost focus")
 }
 
 // SetFocusReportingEnabled sets whether focus reporting is enabled
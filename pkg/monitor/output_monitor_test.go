@@ -1,7 +1,9 @@
 package monitor
 
 import (
-	"os"
+	"bytes"
+	"log/slog"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -221,9 +223,6 @@ func TestOutputMonitor_HandleData(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Clear debug env
-			_ = os.Unsetenv("CLAUDE_NOTIFY_DEBUG")
-
 			cfg := &config.Config{}
 			mockNotifier := &MockBackstopNotifier{}
 			om := NewOutputMonitor(cfg, mockNotifier)
@@ -311,6 +310,19 @@ func TestOutputMonitor_BellDetection(t *testing.T) {
 	}
 }
 
+func TestOutputMonitor_BellDebugLog(t *testing.T) {
+	var log bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&log, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	om := NewOutputMonitor(&config.Config{}, &MockBackstopNotifier{})
+	om.HandleData([]byte("done\x07\n"))
+
+	if !strings.Contains(log.String(), `level=DEBUG msg="bell detected, disabling backstop timer"`) {
+		t.Errorf("expected bell to be logged at debug level, got %q", log.String())
+	}
+}
+
 func TestOutputMonitor_LastOutputTime(t *testing.T) {
 	cfg := &config.Config{}
 	mockNotifier := &MockNotifier{}
@@ -359,3 +371,132 @@ func TestOutputMonitor_FlushPartialLine(t *testing.T) {
 		t.Error("bell should be detected after flush")
 	}
 }
+
+func TestOutputMonitor_FirstOutputTimeout(t *testing.T) {
+	tests := []struct {
+		name         string
+		notStarted   bool
+		output       []byte
+		expectNotify bool
+	}{
+		{"no output fires notification", false, nil, true},
+		{"early output cancels notification", false, []byte("Welcome to Claude Code\n"), false},
+		{"escape sequences alone don't cancel", false, []byte("\x1b[?1049h\x1b[2J"), true},
+		{"not started yet", true, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{FirstOutputTimeout: 40 * time.Millisecond}
+			mockNotifier := &MockNotifier{}
+			alertNotifier := &MockNotifier{}
+			om := NewOutputMonitor(cfg, mockNotifier)
+			om.SetAlertNotifier(alertNotifier)
+			defer om.Close()
+
+			if !tt.notStarted {
+				om.HandleStart()
+			}
+			if tt.output != nil {
+				om.HandleData(tt.output)
+			}
+
+			time.Sleep(80 * time.Millisecond)
+
+			if got := mockNotifier.GetSent(); len(got) != 0 {
+				t.Errorf("expected the alert to bypass the main notifier, got %d", len(got))
+			}
+			sent := alertNotifier.GetSent()
+			if tt.expectNotify {
+				if len(sent) != 1 {
+					t.Fatalf("expected 1 notification, got %d", len(sent))
+				}
+				if sent[0].Pattern != "first_output" {
+					t.Errorf("expected pattern first_output, got %q", sent[0].Pattern)
+				}
+			} else if len(sent) != 0 {
+				t.Errorf("expected no notifications, got %d", len(sent))
+			}
+		})
+	}
+}
+
+func TestOutputMonitor_HangDetection(t *testing.T) {
+	cfg := &config.Config{HangTimeout: time.Minute}
+	backstopNotifier := &MockBackstopNotifier{}
+	mockNotifier := &MockNotifier{}
+	om := NewOutputMonitor(cfg, backstopNotifier)
+	om.SetAlertNotifier(mockNotifier)
+	defer om.Close()
+
+	start := time.Now()
+	current := start
+	om.now = func() time.Time { return current }
+	om.lastLineTime = start
+
+	// waitForSent waits for the asynchronous hang notification to arrive
+	waitForSent := func(want int) []notification.Notification {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for len(mockNotifier.GetSent()) < want && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+		time.Sleep(20 * time.Millisecond) // catch unexpected extra sends
+		return mockNotifier.GetSent()
+	}
+
+	spin := func(until time.Duration) {
+		for elapsed := time.Duration(0); elapsed <= until; elapsed += 5 * time.Second {
+			current = start.Add(elapsed)
+			om.HandleData([]byte("\r✻ Thinking… (esc to interrupt)"))
+		}
+	}
+
+	// Spinner frames within the timeout don't notify
+	spin(50 * time.Second)
+	if sent := waitForSent(0); len(sent) != 0 {
+		t.Fatalf("expected no notification before the timeout, got %d", len(sent))
+	}
+
+	// Past the timeout, exactly one notification for the whole hang
+	spin(3 * time.Minute)
+	sent := waitForSent(1)
+	if len(sent) != 1 {
+		t.Fatalf("expected 1 hang notification, got %d", len(sent))
+	}
+	if sent[0].Pattern != "hang" {
+		t.Errorf("expected pattern hang, got %q", sent[0].Pattern)
+	}
+
+	// A completed line is progress and re-arms the detector
+	start = start.Add(3 * time.Minute)
+	current = start
+	om.HandleData([]byte("Wrote 3 files\r\n"))
+	spin(30 * time.Second)
+	if sent := waitForSent(1); len(sent) != 1 {
+		t.Fatalf("expected no new notification right after progress, got %d", len(sent))
+	}
+	spin(2 * time.Minute)
+	if sent := waitForSent(2); len(sent) != 2 {
+		t.Fatalf("expected a second hang notification, got %d", len(sent))
+	}
+
+	// Hang alerts bypass the backstop, so they don't re-arm it
+	if sent := backstopNotifier.GetSent(); len(sent) != 0 {
+		t.Errorf("expected no notifications through the backstop, got %d", len(sent))
+	}
+}
+
+func TestOutputMonitor_HangDetectionDisabled(t *testing.T) {
+	mockNotifier := &MockNotifier{}
+	om := NewOutputMonitor(&config.Config{}, mockNotifier)
+	defer om.Close()
+
+	om.now = func() time.Time { return time.Now().Add(time.Hour) }
+	om.HandleData([]byte("\r✻ Thinking…"))
+
+	time.Sleep(20 * time.Millisecond)
+	if sent := mockNotifier.GetSent(); len(sent) != 0 {
+		t.Errorf("expected no notifications, got %d", len(sent))
+	}
+}
@@ -0,0 +1,23 @@
+package notification
+
+// AffixNotifier wraps another notifier and adds a fixed prefix and suffix to every message
+type AffixNotifier struct {
+	underlying Notifier
+	prefix     string
+	suffix     string
+}
+
+// NewAffixNotifier creates a new affix notifier
+func NewAffixNotifier(underlying Notifier, prefix, suffix string) *AffixNotifier {
+	return &AffixNotifier{
+		underlying: underlying,
+		prefix:     prefix,
+		suffix:     suffix,
+	}
+}
+
+// Send implements the Notifier interface
+func (an *AffixNotifier) Send(notification Notification) error {
+	notification.Message = an.prefix + notification.Message + an.suffix
+	return an.underlying.Send(notification)
+}
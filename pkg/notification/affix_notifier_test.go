@@ -0,0 +1,61 @@
+package notification
+
+import "testing"
+
+func TestAffixNotifier(t *testing.T) {
+	tests := []struct {
+		name        string
+		prefix      string
+		suffix      string
+		message     string
+		wantMessage string
+	}{
+		{"prefix only", "[dev] ", "", "No activity detected", "[dev] No activity detected"},
+		{"suffix only", "", " (laptop)", "No activity detected", "No activity detected (laptop)"},
+		{"prefix and suffix", "[dev] ", " (laptop)", "done", "[dev] done (laptop)"},
+		{"empty message", "[dev] ", "", "", "[dev] "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &testNotifier{}
+			an := NewAffixNotifier(mock, tt.prefix, tt.suffix)
+
+			if err := an.Send(Notification{Title: "Test", Message: tt.message}); err != nil {
+				t.Fatalf("Send failed: %v", err)
+			}
+
+			sent := mock.getNotifications()
+			if len(sent) != 1 {
+				t.Fatalf("expected 1 notification, got %d", len(sent))
+			}
+			if sent[0].Message != tt.wantMessage {
+				t.Errorf("Message = %q, want %q", sent[0].Message, tt.wantMessage)
+			}
+			if sent[0].Title != "Test" {
+				t.Errorf("Title = %q, want it unchanged", sent[0].Title)
+			}
+		})
+	}
+}
+
+func TestAffixNotifier_WithContextNotifier(t *testing.T) {
+	mock := &testNotifier{}
+	// Same order as NewDependencies: context formatting wraps the affix notifier
+	cn := NewContextNotifier(NewAffixNotifier(mock, "[dev] ", "!"), func() string { return "Fix tests" })
+
+	for i := 0; i < 2; i++ {
+		if err := cn.Send(Notification{Title: "Original", Message: "waiting"}); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+	}
+
+	for _, n := range mock.getNotifications() {
+		if n.Message != "[dev] waiting!" {
+			t.Errorf("Message = %q, want %q", n.Message, "[dev] waiting!")
+		}
+		if n.Title == "Original" {
+			t.Error("expected context notifier to set the title")
+		}
+	}
+}
@@ -15,6 +15,12 @@ type BackstopNotifier struct {
 	underlying Notifier
 	timeout    time.Duration
 
+	// The timeout shrinks towards minTimeout as the session ages
+	minTimeout time.Duration
+	decay      time.Duration
+	startTime  time.Time
+	now        func() time.Time
+
 	mu                                       sync.Mutex
 	lastNotificationTime                     time.Time
 	lastActivityTime                         time.Time
@@ -22,6 +28,7 @@ type BackstopNotifier struct {
 	timer                                    *time.Timer
 	backstopSent                             bool // Track if backstop notification was sent for current session
 	backstopDisabled                         bool // Track if backstop timer has been disabled by user input
+	hadActivity                              bool // Track if Claude has output anything in the current session
 	idleNotificationSentSinceLastInteraction bool // Track if we've sent an idle notification since last user interaction
 }
 
@@ -32,6 +39,8 @@ func NewBackstopNotifier(underlying Notifier, timeout time.Duration) *BackstopNo
 		timeout:             timeout,
 		lastActivityTime:    time.Now(),
 		lastUserInteraction: time.Now(),
+		startTime:           time.Now(),
+		now:                 time.Now,
 	}
 
 	if timeout > 0 {
@@ -41,6 +50,35 @@ func NewBackstopNotifier(underlying Notifier, timeout time.Duration) *BackstopNo
 	return bn
 }
 
+// SetTimeoutDecay makes the timeout shrink linearly from its initial value to minTimeout
+// over the first decay of the session, so inactivity later on is reported sooner
+func (bn *BackstopNotifier) SetTimeoutDecay(minTimeout, decay time.Duration) {
+	bn.mu.Lock()
+	defer bn.mu.Unlock()
+
+	bn.minTimeout = minTimeout
+	bn.decay = decay
+}
+
+// currentTimeout returns the inactivity timeout for the session's current age.
+// Callers must hold bn.mu.
+func (bn *BackstopNotifier) currentTimeout() time.Duration {
+	if bn.decay <= 0 || bn.minTimeout <= 0 || bn.minTimeout >= bn.timeout {
+		return bn.timeout
+	}
+
+	age := bn.now().Sub(bn.startTime)
+	if age >= bn.decay {
+		return bn.minTimeout
+	}
+	if age <= 0 {
+		return bn.timeout
+	}
+
+	shrink := time.Duration(float64(bn.timeout-bn.minTimeout) * float64(age) / float64(bn.decay))
+	return bn.timeout - shrink
+}
+
 // Send implements the Notifier interface
 func (bn *BackstopNotifier) Send(notification Notification) error {
 	bn.mu.Lock()
@@ -59,7 +97,7 @@ func (bn *BackstopNotifier) Send(notification Notification) error {
 	}
 	// Always restart timer after a notification
 	if bn.timeout > 0 {
-		bn.timer = time.AfterFunc(bn.timeout, bn.sendBackstopNotification)
+		bn.timer = time.AfterFunc(bn.currentTimeout(), bn.sendBackstopNotification)
 	}
 
 	// Forward to underlying notifier
@@ -72,6 +110,7 @@ func (bn *BackstopNotifier) MarkActivity() {
 	defer bn.mu.Unlock()
 
 	bn.lastActivityTime = time.Now()
+	bn.hadActivity = true
 
 	// Reset backstop sent flag and disabled flag since we have new activity
 	bn.backstopSent = false
@@ -83,7 +122,7 @@ func (bn *BackstopNotifier) MarkActivity() {
 	}
 	// Always restart timer after activity
 	if bn.timeout > 0 {
-		bn.timer = time.AfterFunc(bn.timeout, bn.sendBackstopNotification)
+		bn.timer = time.AfterFunc(bn.currentTimeout(), bn.sendBackstopNotification)
 	}
 }
 
@@ -102,10 +141,16 @@ func (bn *BackstopNotifier) sendBackstopNotification() {
 		return
 	}
 
+	// Claude going quiet after working likely means the task is done
+	message := "No output yet"
+	if bn.hadActivity {
+		message = "Claude went quiet, the task may be complete"
+	}
+
 	// Send backstop notification
 	notification := Notification{
 		Title:   "Claude needs attention",
-		Message: "No activity detected",
+		Message: message,
 		Time:    time.Now(),
 		Pattern: "backstop",
 	}
@@ -126,7 +171,7 @@ func (bn *BackstopNotifier) startTimer() {
 	defer bn.mu.Unlock()
 
 	if bn.timeout > 0 {
-		bn.timer = time.AfterFunc(bn.timeout, bn.sendBackstopNotification)
+		bn.timer = time.AfterFunc(bn.currentTimeout(), bn.sendBackstopNotification)
 	}
 }
 
@@ -150,6 +195,7 @@ func (bn *BackstopNotifier) ResetSession() {
 
 	bn.backstopSent = false
 	bn.backstopDisabled = false
+	bn.hadActivity = false
 	bn.lastActivityTime = time.Now()
 	// Reset idle notification flag since this is a new session that warrants attention
 	bn.idleNotificationSentSinceLastInteraction = false
@@ -160,7 +206,7 @@ func (bn *BackstopNotifier) ResetSession() {
 	}
 	// Start a new timer for the new session
 	if bn.timeout > 0 {
-		bn.timer = time.AfterFunc(bn.timeout, bn.sendBackstopNotification)
+		bn.timer = time.AfterFunc(bn.currentTimeout(), bn.sendBackstopNotification)
 	}
 }
 
@@ -220,3 +220,88 @@ func TestBackstopNotifier_BellDisablesBackstop(t *testing.T) {
 		t.Errorf("Expected no backstop notifications after bell, got %d", backstopCount)
 	}
 }
+
+func TestBackstopNotifier_TimeoutDecay(t *testing.T) {
+	tests := []struct {
+		name       string
+		minTimeout time.Duration
+		decay      time.Duration
+		age        time.Duration
+		want       time.Duration
+	}{
+		{name: "session start", minTimeout: time.Minute, decay: time.Hour, age: 0, want: 5 * time.Minute},
+		{name: "half way", minTimeout: time.Minute, decay: time.Hour, age: 30 * time.Minute, want: 3 * time.Minute},
+		{name: "fully decayed", minTimeout: time.Minute, decay: time.Hour, age: time.Hour, want: time.Minute},
+		{name: "long after", minTimeout: time.Minute, decay: time.Hour, age: 5 * time.Hour, want: time.Minute},
+		{name: "decay disabled", age: 5 * time.Hour, want: 5 * time.Minute},
+		{name: "minimum above timeout", minTimeout: 10 * time.Minute, decay: time.Hour, age: time.Hour, want: 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bn := NewBackstopNotifier(&testNotifier{}, 5*time.Minute)
+			defer func() { _ = bn.Close() }()
+			bn.SetTimeoutDecay(tt.minTimeout, tt.decay)
+
+			start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+			bn.mu.Lock()
+			bn.startTime = start
+			bn.now = func() time.Time { return start.Add(tt.age) }
+			got := bn.currentTimeout()
+			bn.mu.Unlock()
+
+			if got != tt.want {
+				t.Errorf("currentTimeout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackstopNotifier_MessageDependsOnActivity(t *testing.T) {
+	tests := []struct {
+		name        string
+		prepare     func(bn *BackstopNotifier)
+		wantMessage string
+	}{
+		{
+			name:        "no output",
+			prepare:     func(bn *BackstopNotifier) {},
+			wantMessage: "No output yet",
+		},
+		{
+			name:        "quiet after output",
+			prepare:     func(bn *BackstopNotifier) { bn.MarkActivity() },
+			wantMessage: "Claude went quiet, the task may be complete",
+		},
+		{
+			name: "new session without output",
+			prepare: func(bn *BackstopNotifier) {
+				bn.MarkActivity()
+				bn.ResetSession()
+			},
+			wantMessage: "No output yet",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &testNotifier{}
+			backstop := NewBackstopNotifier(mock, 50*time.Millisecond)
+			defer func() { _ = backstop.Close() }()
+
+			tt.prepare(backstop)
+			time.Sleep(100 * time.Millisecond)
+
+			notifications := mock.getNotifications()
+			if len(notifications) != 1 {
+				t.Fatalf("Expected 1 notification, got %d", len(notifications))
+			}
+			if notifications[0].Title != "Claude needs attention" {
+				t.Errorf("Expected title %q, got %q", "Claude needs attention", notifications[0].Title)
+			}
+			if notifications[0].Message != tt.wantMessage {
+				t.Errorf("Expected message %q, got %q", tt.wantMessage, notifications[0].Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package notification
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"text/template"
+)
+
+// ClickData is the data available to the click_url template
+type ClickData struct {
+	Title         string
+	Message       string
+	Pattern       string
+	TerminalTitle string
+}
+
+// ClickTemplate builds the URL opened when a notification is tapped
+type ClickTemplate struct {
+	tmpl *template.Template
+}
+
+// ParseClickTemplate parses a click_url template such as "https://ci.example.com/{{.Pattern}}"
+func ParseClickTemplate(text string) (*ClickTemplate, error) {
+	tmpl, err := template.New("click_url").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid click_url template: %w", err)
+	}
+	return &ClickTemplate{tmpl: tmpl}, nil
+}
+
+// Expand renders the template for the given data
+func (ct *ClickTemplate) Expand(data ClickData) (string, error) {
+	var b strings.Builder
+	if err := ct.tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to expand click_url: %w", err)
+	}
+	return b.String(), nil
+}
+
+// ClickNotifier wraps another notifier and sets each notification's click URL from a template
+type ClickNotifier struct {
+	underlying   Notifier
+	template     *ClickTemplate
+	terminalInfo func() string
+}
+
+// NewClickNotifier creates a new click URL notifier
+func NewClickNotifier(underlying Notifier, template *ClickTemplate, terminalInfo func() string) *ClickNotifier {
+	return &ClickNotifier{
+		underlying:   underlying,
+		template:     template,
+		terminalInfo: terminalInfo,
+	}
+}
+
+// Send implements the Notifier interface
+func (cn *ClickNotifier) Send(notification Notification) error {
+	data := ClickData{
+		Title:   notification.Title,
+		Message: notification.Message,
+		Pattern: notification.Pattern,
+	}
+	if cn.terminalInfo != nil {
+		data.TerminalTitle = cn.terminalInfo()
+	}
+
+	// A broken template shouldn't stop the notification itself
+	if click, err := cn.template.Expand(data); err != nil {
+		slog.Warn("sending the notification without a click URL", "err", err)
+	} else {
+		notification.Click = click
+	}
+
+	return cn.underlying.Send(notification)
+}
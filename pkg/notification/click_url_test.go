@@ -0,0 +1,95 @@
+package notification
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClickTemplate_Expand(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		data     ClickData
+		want     string
+	}{
+		{
+			name:     "pattern",
+			template: "https://ci.example.com/{{.Pattern}}",
+			data:     ClickData{Pattern: "backstop"},
+			want:     "https://ci.example.com/backstop",
+		},
+		{
+			name:     "query escaped terminal title",
+			template: "https://example.com/?task={{urlquery .TerminalTitle}}",
+			data:     ClickData{TerminalTitle: "Fix tests"},
+			want:     "https://example.com/?task=Fix+tests",
+		},
+		{
+			name:     "static url",
+			template: "tmux://session/main",
+			data:     ClickData{Pattern: "startup"},
+			want:     "tmux://session/main",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ct, err := ParseClickTemplate(tt.template)
+			if err != nil {
+				t.Fatalf("ParseClickTemplate() error = %v", err)
+			}
+			got, err := ct.Expand(tt.data)
+			if err != nil {
+				t.Fatalf("Expand() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseClickTemplate_Invalid(t *testing.T) {
+	_, err := ParseClickTemplate("https://example.com/{{.Pattern")
+	if err == nil || !strings.Contains(err.Error(), "invalid click_url template") {
+		t.Errorf("expected invalid template error, got %v", err)
+	}
+}
+
+func TestClickNotifier(t *testing.T) {
+	mock := &testNotifier{}
+	ct, err := ParseClickTemplate("https://example.com/{{.Pattern}}/{{.TerminalTitle}}")
+	if err != nil {
+		t.Fatalf("ParseClickTemplate() error = %v", err)
+	}
+
+	cn := NewClickNotifier(mock, ct, func() string { return "build" })
+	if err := cn.Send(Notification{Title: "Test", Pattern: "backstop"}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	sent := mock.getNotifications()
+	if len(sent) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(sent))
+	}
+	if sent[0].Click != "https://example.com/backstop/build" {
+		t.Errorf("Click = %q, want https://example.com/backstop/build", sent[0].Click)
+	}
+}
+
+func TestClickNotifier_ExpandErrorStillSends(t *testing.T) {
+	mock := &testNotifier{}
+	ct, err := ParseClickTemplate("https://example.com/{{.Missing}}")
+	if err != nil {
+		t.Fatalf("ParseClickTemplate() error = %v", err)
+	}
+
+	if err := NewClickNotifier(mock, ct, nil).Send(Notification{Title: "Test"}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	sent := mock.getNotifications()
+	if len(sent) != 1 || sent[0].Click != "" {
+		t.Errorf("expected notification without a click URL, got %v", sent)
+	}
+}
@@ -8,9 +8,11 @@ import (
 
 // ContextNotifier wraps another notifier and adds context to notifications
 type ContextNotifier struct {
-	underlying   Notifier
-	cwdBasename  string
-	terminalInfo func() string
+	underlying       Notifier
+	cwdBasename      string
+	terminalInfo     func() string
+	messageFromTitle bool
+	maxTitleLength   int
 }
 
 // NewContextNotifier creates a new context notifier
@@ -29,6 +31,29 @@ func NewContextNotifier(underlying Notifier, terminalInfo func() string) *Contex
 	}
 }
 
+// SetMessageFromTitle makes the terminal title, when set, the notification message
+func (cn *ContextNotifier) SetMessageFromTitle(enabled bool) {
+	cn.messageFromTitle = enabled
+}
+
+// SetMaxTitleLength cuts terminal titles longer than n characters in the context.
+// Zero leaves them whole.
+func (cn *ContextNotifier) SetMaxTitleLength(n int) {
+	cn.maxTitleLength = n
+}
+
+// UseGitContext labels notifications with the git repository and branch instead of
+// the directory name, when the working directory is inside a repository
+func (cn *ContextNotifier) UseGitContext() {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return
+	}
+	if info, ok := ReadGitInfo(cwd); ok {
+		cn.cwdBasename = info.Label()
+	}
+}
+
 // Send implements the Notifier interface
 func (cn *ContextNotifier) Send(notification Notification) error {
 	// Add context to title
@@ -40,10 +65,16 @@ func (cn *ContextNotifier) Send(notification Notification) error {
 			// Parse out the Claude icon and clean up the title
 			cleanTitle := cn.cleanTerminalTitle(title)
 			if cleanTitle != "" && cleanTitle != "claude" {
+				contextTitle := truncateTitle(cleanTitle, cn.maxTitleLength)
 				if context != "" {
-					context = context + " - " + cleanTitle
+					context = context + " - " + contextTitle
 				} else {
-					context = cleanTitle
+					context = contextTitle
+				}
+
+				// Claude keeps its task status in the title
+				if cn.messageFromTitle {
+					notification.Message = cleanTitle
 				}
 			}
 		}
@@ -104,3 +135,16 @@ func (cn *ContextNotifier) cleanTerminalTitle(title string) string {
 
 	return strings.TrimSpace(cleaned)
 }
+
+// truncateTitle cuts title to at most limit runes followed by an ellipsis.
+// A limit of zero or less leaves the title whole.
+func truncateTitle(title string, limit int) string {
+	if limit <= 0 {
+		return title
+	}
+	runes := []rune(title)
+	if len(runes) <= limit {
+		return title
+	}
+	return strings.TrimSpace(string(runes[:limit])) + "…"
+}
@@ -92,6 +92,83 @@ func TestContextNotifier(t *testing.T) {
 	}
 }
 
+func TestContextNotifier_MessageFromTitle(t *testing.T) {
+	tests := []struct {
+		name          string
+		enabled       bool
+		terminalTitle string
+		wantMessage   string
+	}{
+		{"title replaces message", true, "✳ Running tests", "Running tests"},
+		{"disabled keeps message", false, "✳ Running tests", "No activity detected"},
+		{"no title keeps message", true, "", "No activity detected"},
+		{"plain claude title keeps message", true, "claude", "No activity detected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &testNotifier{}
+			cn := NewContextNotifier(mock, func() string { return tt.terminalTitle })
+			cn.SetMessageFromTitle(tt.enabled)
+
+			if err := cn.Send(Notification{Title: "Claude needs attention", Message: "No activity detected"}); err != nil {
+				t.Fatalf("Send failed: %v", err)
+			}
+
+			sent := mock.getNotifications()
+			if len(sent) != 1 {
+				t.Fatalf("expected 1 notification, got %d", len(sent))
+			}
+			if sent[0].Message != tt.wantMessage {
+				t.Errorf("Message = %q, want %q", sent[0].Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestContextNotifier_MaxTitleLength(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxLength     int
+		terminalTitle string
+		wantContext   string
+		wantMessage   string
+	}{
+		{"long title is cut", 10, "✳ npm run test -- --coverage --watch", "npm run te…", "npm run test -- --coverage --watch"},
+		{"short title is untouched", 10, "✳ Fix bug", "Fix bug", "Fix bug"},
+		{"title at the limit is untouched", 7, "✳ Fix bug", "Fix bug", "Fix bug"},
+		{"cut keeps runes whole", 4, "✳ Über große Änderung", "Über…", "Über große Änderung"},
+		{"trailing space is dropped", 4, "✳ Fix the bug", "Fix…", "Fix the bug"},
+		{"zero leaves title whole", 0, "✳ npm run test -- --coverage --watch", "npm run test -- --coverage --watch", "npm run test -- --coverage --watch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &testNotifier{}
+			cn := NewContextNotifier(mock, func() string { return tt.terminalTitle })
+			cn.cwdBasename = ""
+			cn.SetMessageFromTitle(true)
+			cn.SetMaxTitleLength(tt.maxLength)
+
+			if err := cn.Send(Notification{Title: "Claude needs attention", Message: "No activity detected"}); err != nil {
+				t.Fatalf("Send failed: %v", err)
+			}
+
+			sent := mock.getNotifications()
+			if len(sent) != 1 {
+				t.Fatalf("expected 1 notification, got %d", len(sent))
+			}
+			if want := "Claude Code: " + tt.wantContext; sent[0].Title != want {
+				t.Errorf("Title = %q, want %q", sent[0].Title, want)
+			}
+			// The message has room for the whole title
+			if sent[0].Message != tt.wantMessage {
+				t.Errorf("Message = %q, want %q", sent[0].Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
 func TestCleanTerminalTitle(t *testing.T) {
 	cn := &ContextNotifier{}
 
@@ -0,0 +1,81 @@
+package notification
+
+import (
+	"os/exec"
+	"time"
+)
+
+// DesktopRunner runs a command and returns its combined output
+type DesktopRunner func(name string, args ...string) ([]byte, error)
+
+// runDesktopCommand runs the command directly, without a shell
+func runDesktopCommand(name string, args ...string) ([]byte, error) {
+	return exec.Command(name, args...).CombinedOutput()
+}
+
+// desktopRetryDelay is the delay before the first retry of a failed desktop notification;
+// it doubles for each further retry
+const desktopRetryDelay = 250 * time.Millisecond
+
+// runWithRetries runs the command, retrying a failure up to retries times with backoff,
+// e.g. while dbus is not ready yet
+func runWithRetries(run DesktopRunner, sleep func(time.Duration), retries int, name string, args ...string) ([]byte, error) {
+	delay := desktopRetryDelay
+	for attempt := 0; ; attempt++ {
+		output, err := run(name, args...)
+		if err == nil || attempt >= retries {
+			return output, err
+		}
+		sleep(delay)
+		delay *= 2
+	}
+}
+
+// macOS notifier tools
+const (
+	MacOSNotifierAuto             = "auto"
+	MacOSNotifierTerminalNotifier = "terminal-notifier"
+	MacOSNotifierOsascript        = "osascript"
+)
+
+// DesktopOptions configures the desktop notifier
+type DesktopOptions struct {
+	// MacOSNotifier picks the macOS tool; terminal-notifier is preferred unless this is osascript
+	MacOSNotifier string
+
+	// FocusTerminal brings the terminal to the front when a notification is clicked
+	FocusTerminal bool
+
+	// Retries is how many times a failed notification command is retried
+	Retries int
+
+	// TmuxPane is Claude's tmux pane (e.g. $TMUX_PANE); with FocusTerminal a click also selects it
+	TmuxPane string
+}
+
+// tmuxFocusCommands returns the tmux commands that bring pane to the front of its client
+func tmuxFocusCommands(pane string) [][]string {
+	return [][]string{
+		{"switch-client", "-t", pane},
+		{"select-window", "-t", pane},
+		{"select-pane", "-t", pane},
+	}
+}
+
+// terminalBundleIDs maps $TERM_PROGRAM to the terminal's macOS bundle id
+var terminalBundleIDs = map[string]string{
+	"Apple_Terminal": "com.apple.Terminal",
+	"iTerm.app":      "com.googlecode.iterm2",
+	"vscode":         "com.microsoft.VSCode",
+	"WezTerm":        "com.github.wez.wezterm",
+	"ghostty":        "com.mitchellh.ghostty",
+}
+
+// terminalBundleID returns the bundle id of the terminal app we run in, or "" if unknown
+func terminalBundleID(getenv func(string) string) string {
+	// macOS sets this for processes launched from an app
+	if id := getenv("__CFBundleIdentifier"); id != "" {
+		return id
+	}
+	return terminalBundleIDs[getenv("TERM_PROGRAM")]
+}
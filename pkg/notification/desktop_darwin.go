@@ -0,0 +1,128 @@
+//go:build darwin
+// +build darwin
+
+package notification
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// DarwinDesktopNotifier shows notifications in the macOS Notification Center.
+// It uses terminal-notifier when available, so notifications carry the terminal's
+// icon and can focus the terminal when clicked, and osascript otherwise.
+type DarwinDesktopNotifier struct {
+	run           DesktopRunner
+	lookPath      func(file string) (string, error)
+	sleep         func(time.Duration)
+	retries       int
+	tool          string
+	bundleID      string
+	focusTerminal bool
+	tmuxPane      string
+}
+
+// NewDarwinDesktopNotifier creates a new Notification Center notifier
+func NewDarwinDesktopNotifier(opts DesktopOptions) *DarwinDesktopNotifier {
+	return &DarwinDesktopNotifier{
+		run:           runDesktopCommand,
+		lookPath:      exec.LookPath,
+		sleep:         time.Sleep,
+		retries:       opts.Retries,
+		tool:          opts.MacOSNotifier,
+		bundleID:      terminalBundleID(os.Getenv),
+		focusTerminal: opts.FocusTerminal,
+		tmuxPane:      opts.TmuxPane,
+	}
+}
+
+// NewDesktopNotifier creates the native desktop notifier for this platform
+func NewDesktopNotifier(opts DesktopOptions) (Notifier, error) {
+	return NewDarwinDesktopNotifier(opts), nil
+}
+
+// Send implements the Notifier interface
+func (dn *DarwinDesktopNotifier) Send(notification Notification) error {
+	if dn.useTerminalNotifier() {
+		return dn.sendTerminalNotifier(notification)
+	}
+	return dn.sendOsascript(notification)
+}
+
+// useTerminalNotifier reports whether terminal-notifier should be used.
+// Without it installed we fall back to osascript, even if it was asked for.
+func (dn *DarwinDesktopNotifier) useTerminalNotifier() bool {
+	if dn.tool == MacOSNotifierOsascript {
+		return false
+	}
+	_, err := dn.lookPath("terminal-notifier")
+	return err == nil
+}
+
+// sendTerminalNotifier shows the notification with terminal-notifier
+func (dn *DarwinDesktopNotifier) sendTerminalNotifier(notification Notification) error {
+	args := []string{"-title", notification.Title, "-message", notification.Message}
+	if dn.focusTerminal {
+		// terminal-notifier ignores -activate and -execute when -sender is given,
+		// so the terminal's icon is given up for the click actions
+		if dn.bundleID != "" {
+			args = append(args, "-activate", dn.bundleID)
+		}
+		if dn.tmuxPane != "" {
+			args = append(args, "-execute", dn.tmuxFocusScript())
+		}
+	} else if dn.bundleID != "" {
+		// Show the terminal's icon
+		args = append(args, "-sender", dn.bundleID)
+	}
+
+	if output, err := runWithRetries(dn.run, dn.sleep, dn.retries, "terminal-notifier", args...); err != nil {
+		return fmt.Errorf("terminal-notifier failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// tmuxFocusScript returns the shell command terminal-notifier runs on click to select our pane.
+// It runs without our PATH, so tmux is referred to by its full path when we can find it.
+func (dn *DarwinDesktopNotifier) tmuxFocusScript() string {
+	tmux := "tmux"
+	if path, err := dn.lookPath("tmux"); err == nil {
+		tmux = path
+	}
+
+	commands := make([]string, 0, 3)
+	for _, args := range tmuxFocusCommands(dn.tmuxPane) {
+		words := []string{shellQuote(tmux)}
+		for _, arg := range args {
+			words = append(words, shellQuote(arg))
+		}
+		commands = append(commands, strings.Join(words, " "))
+	}
+	return strings.Join(commands, "; ")
+}
+
+// sendOsascript shows the notification with AppleScript
+func (dn *DarwinDesktopNotifier) sendOsascript(notification Notification) error {
+	script := fmt.Sprintf("display notification %s with title %s",
+		appleScriptString(notification.Message), appleScriptString(notification.Title))
+
+	if output, err := runWithRetries(dn.run, dn.sleep, dn.retries, "osascript", "-e", script); err != nil {
+		return fmt.Errorf("osascript failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// shellQuote quotes s as a single POSIX shell word
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,134 @@
+//go:build darwin
+// +build darwin
+
+package notification
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestDarwinNotifier returns a notifier that records commands instead of running them
+func newTestDarwinNotifier(opts DesktopOptions, bundleID string, haveTerminalNotifier bool) (*DarwinDesktopNotifier, *[]string) {
+	var commands []string
+	dn := NewDarwinDesktopNotifier(opts)
+	dn.bundleID = bundleID
+	dn.run = func(name string, args ...string) ([]byte, error) {
+		commands = append(commands, name+"|"+strings.Join(args, "|"))
+		return nil, nil
+	}
+	dn.lookPath = func(file string) (string, error) {
+		if haveTerminalNotifier {
+			return "/opt/homebrew/bin/" + file, nil
+		}
+		return "", errors.New("not found")
+	}
+	return dn, &commands
+}
+
+func TestDarwinDesktopNotifier(t *testing.T) {
+	tests := []struct {
+		name                 string
+		opts                 DesktopOptions
+		bundleID             string
+		haveTerminalNotifier bool
+		want                 string
+	}{
+		{
+			name:                 "terminal-notifier with terminal icon",
+			opts:                 DesktopOptions{MacOSNotifier: MacOSNotifierAuto},
+			bundleID:             "com.googlecode.iterm2",
+			haveTerminalNotifier: true,
+			want:                 `terminal-notifier|-title|Claude Code: "api"|-message|path\to "done"|-sender|com.googlecode.iterm2`,
+		},
+		{
+			name:                 "focus terminal on click",
+			opts:                 DesktopOptions{FocusTerminal: true},
+			bundleID:             "com.googlecode.iterm2",
+			haveTerminalNotifier: true,
+			want:                 `terminal-notifier|-title|Claude Code: "api"|-message|path\to "done"|-activate|com.googlecode.iterm2`,
+		},
+		{
+			name:                 "focus tmux pane on click",
+			opts:                 DesktopOptions{FocusTerminal: true, TmuxPane: "%3"},
+			bundleID:             "com.apple.Terminal",
+			haveTerminalNotifier: true,
+			want: `terminal-notifier|-title|Claude Code: "api"|-message|path\to "done"|-activate|com.apple.Terminal|-execute|` +
+				`'/opt/homebrew/bin/tmux' 'switch-client' '-t' '%3'; '/opt/homebrew/bin/tmux' 'select-window' '-t' '%3'; '/opt/homebrew/bin/tmux' 'select-pane' '-t' '%3'`,
+		},
+		{
+			name:                 "terminal-notifier in unknown terminal",
+			opts:                 DesktopOptions{MacOSNotifier: MacOSNotifierTerminalNotifier},
+			haveTerminalNotifier: true,
+			want:                 `terminal-notifier|-title|Claude Code: "api"|-message|path\to "done"`,
+		},
+		{
+			name:                 "falls back to osascript",
+			opts:                 DesktopOptions{MacOSNotifier: MacOSNotifierTerminalNotifier, FocusTerminal: true},
+			bundleID:             "com.apple.Terminal",
+			haveTerminalNotifier: false,
+			want:                 `osascript|-e|display notification "path\\to \"done\"" with title "Claude Code: \"api\""`,
+		},
+		{
+			name:                 "osascript requested",
+			opts:                 DesktopOptions{MacOSNotifier: MacOSNotifierOsascript},
+			haveTerminalNotifier: true,
+			want:                 `osascript|-e|display notification "path\\to \"done\"" with title "Claude Code: \"api\""`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dn, commands := newTestDarwinNotifier(tt.opts, tt.bundleID, tt.haveTerminalNotifier)
+
+			err := dn.Send(Notification{Title: `Claude Code: "api"`, Message: `path\to "done"`})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(*commands) != 1 || (*commands)[0] != tt.want {
+				t.Errorf("ran %q, want %q", *commands, tt.want)
+			}
+		})
+	}
+}
+
+func TestDarwinDesktopNotifier_Error(t *testing.T) {
+	dn, _ := newTestDarwinNotifier(DesktopOptions{MacOSNotifier: MacOSNotifierOsascript}, "", false)
+	dn.run = func(name string, args ...string) ([]byte, error) {
+		return []byte("execution error\n"), errors.New("exit status 1")
+	}
+
+	err := dn.Send(Notification{Title: "Test"})
+	if err == nil || !strings.Contains(err.Error(), "execution error") {
+		t.Errorf("expected osascript output in error, got %v", err)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	if got, want := shellQuote("it's"), `'it'\''s'`; got != want {
+		t.Errorf("shellQuote() = %s, want %s", got, want)
+	}
+}
+
+func TestDarwinDesktopNotifier_Retries(t *testing.T) {
+	dn, _ := newTestDarwinNotifier(DesktopOptions{MacOSNotifier: MacOSNotifierOsascript, Retries: 1}, "", false)
+	dn.sleep = func(time.Duration) {}
+	calls := 0
+	dn.run = func(name string, args ...string) ([]byte, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("exit status 1")
+		}
+		return nil, nil
+	}
+
+	if err := dn.Send(Notification{Title: "Test"}); err != nil {
+		t.Fatalf("expected success after a retry, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected osascript to run 2 times, ran %d", calls)
+	}
+}
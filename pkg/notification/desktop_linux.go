@@ -0,0 +1,166 @@
+//go:build linux
+// +build linux
+
+package notification
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DesktopStarter starts a command and returns a function that waits for it to exit
+// and returns its standard output
+type DesktopStarter func(name string, args ...string) (wait func() ([]byte, error), err error)
+
+// startDesktopCommand starts the command directly, without a shell
+func startDesktopCommand(name string, args ...string) (func() ([]byte, error), error) {
+	cmd := exec.Command(name, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	return func() ([]byte, error) {
+		if err := cmd.Wait(); err != nil {
+			return stdout.Bytes(), fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		return stdout.Bytes(), nil
+	}, nil
+}
+
+// startWithRetries starts a command, retrying a failed start up to retries more times
+// with a doubling delay, like runWithRetries
+func startWithRetries(start DesktopStarter, sleep func(time.Duration), retries int, name string, args ...string) (func() ([]byte, error), error) {
+	delay := desktopRetryDelay
+	for attempt := 0; ; attempt++ {
+		wait, err := start(name, args...)
+		if err == nil || attempt >= retries {
+			return wait, err
+		}
+		sleep(delay)
+		delay *= 2
+	}
+}
+
+// LinuxDesktopNotifier shows notifications through libnotify's notify-send
+type LinuxDesktopNotifier struct {
+	run           DesktopRunner
+	start         DesktopStarter
+	lookPath      func(file string) (string, error)
+	sleep         func(time.Duration)
+	retries       int
+	focusTerminal bool
+	tmuxPane      string
+	windowID      string
+
+	actionsOnce sync.Once
+	actions     bool
+}
+
+// NewLinuxDesktopNotifier creates a new notify-send notifier
+func NewLinuxDesktopNotifier(opts DesktopOptions) *LinuxDesktopNotifier {
+	return &LinuxDesktopNotifier{
+		run:           runDesktopCommand,
+		start:         startDesktopCommand,
+		lookPath:      exec.LookPath,
+		sleep:         time.Sleep,
+		retries:       opts.Retries,
+		focusTerminal: opts.FocusTerminal,
+		tmuxPane:      opts.TmuxPane,
+		// X11 terminals set this to their own window
+		windowID: os.Getenv("WINDOWID"),
+	}
+}
+
+// NewDesktopNotifier creates the native desktop notifier for this platform
+func NewDesktopNotifier(opts DesktopOptions) (Notifier, error) {
+	ln := NewLinuxDesktopNotifier(opts)
+	if !ln.IsAvailable() {
+		return nil, fmt.Errorf("desktop notifications need notify-send, which was not found in PATH")
+	}
+	return ln, nil
+}
+
+// IsAvailable reports whether notify-send can be found
+func (ln *LinuxDesktopNotifier) IsAvailable() bool {
+	_, err := ln.lookPath("notify-send")
+	return err == nil
+}
+
+// Send implements the Notifier interface
+func (ln *LinuxDesktopNotifier) Send(notification Notification) error {
+	args := []string{"-u", urgency(notification.Priority)}
+	if ln.focusTerminal && ln.supportsActions() {
+		args = append(args, "--action=focus=Focus terminal", "--wait", "--", notification.Title, notification.Message)
+		wait, err := startWithRetries(ln.start, ln.sleep, ln.retries, "notify-send", args...)
+		if err != nil {
+			return fmt.Errorf("notify-send failed: %w", err)
+		}
+		// notify-send waits until the notification is clicked or closed, so wait in the background
+		go ln.focusOnClick(wait)
+		return nil
+	}
+
+	args = append(args, "--", notification.Title, notification.Message)
+	if output, err := runWithRetries(ln.run, ln.sleep, ln.retries, "notify-send", args...); err != nil {
+		return fmt.Errorf("notify-send failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// supportsActions reports whether notify-send accepts --action, which older libnotify lacks.
+// Without it notifications are sent without click to focus.
+func (ln *LinuxDesktopNotifier) supportsActions() bool {
+	ln.actionsOnce.Do(func() {
+		output, _ := ln.run("notify-send", "--help")
+		ln.actions = strings.Contains(string(output), "--action")
+		if !ln.actions {
+			slog.Info("notify-send does not support --action, click_focus_terminal is ignored")
+		}
+	})
+	return ln.actions
+}
+
+// focusOnClick waits for the notification and focuses the terminal if its action was clicked.
+// Focusing is best effort: xdotool raises the terminal window on X11, tmux selects our pane.
+func (ln *LinuxDesktopNotifier) focusOnClick(wait func() ([]byte, error)) {
+	output, err := wait()
+	if err != nil {
+		slog.Warn("notify-send failed", "err", err)
+		return
+	}
+	if strings.TrimSpace(string(output)) != "focus" {
+		return
+	}
+
+	if ln.windowID != "" {
+		_, _ = ln.run("xdotool", "windowactivate", ln.windowID)
+	}
+	if ln.tmuxPane != "" {
+		for _, cmd := range tmuxFocusCommands(ln.tmuxPane) {
+			_, _ = ln.run("tmux", cmd...)
+		}
+	}
+}
+
+// urgency maps an ntfy priority (1-5, 0 for default) to a notify-send urgency level
+func urgency(priority int) string {
+	switch {
+	case priority == 0:
+		return "normal"
+	case priority <= 2:
+		return "low"
+	case priority >= 4:
+		return "critical"
+	default:
+		return "normal"
+	}
+}
@@ -0,0 +1,260 @@
+//go:build linux
+// +build linux
+
+package notification
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLinuxDesktopNotifier(t *testing.T) {
+	tests := []struct {
+		name        string
+		priority    int
+		wantUrgency string
+	}{
+		{name: "default priority", priority: 0, wantUrgency: "normal"},
+		{name: "min priority", priority: 1, wantUrgency: "low"},
+		{name: "low priority", priority: 2, wantUrgency: "low"},
+		{name: "normal priority", priority: 3, wantUrgency: "normal"},
+		{name: "high priority", priority: 4, wantUrgency: "critical"},
+		{name: "urgent priority", priority: 5, wantUrgency: "critical"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotName string
+			var gotArgs []string
+			ln := NewLinuxDesktopNotifier(DesktopOptions{})
+			ln.run = func(name string, args ...string) ([]byte, error) {
+				gotName = name
+				gotArgs = args
+				return nil, nil
+			}
+
+			err := ln.Send(Notification{Title: "Claude Code: api", Message: "-done", Priority: tt.priority})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			want := []string{"-u", tt.wantUrgency, "--", "Claude Code: api", "-done"}
+			if gotName != "notify-send" || strings.Join(gotArgs, "|") != strings.Join(want, "|") {
+				t.Errorf("expected notify-send %v, got %s %v", want, gotName, gotArgs)
+			}
+		})
+	}
+}
+
+func TestLinuxDesktopNotifier_Error(t *testing.T) {
+	ln := NewLinuxDesktopNotifier(DesktopOptions{})
+	ln.run = func(name string, args ...string) ([]byte, error) {
+		return []byte("cannot connect to dbus\n"), errors.New("exit status 1")
+	}
+
+	err := ln.Send(Notification{Title: "Test"})
+	if err == nil || !strings.Contains(err.Error(), "cannot connect to dbus") {
+		t.Errorf("expected notify-send output in error, got %v", err)
+	}
+}
+
+func TestLinuxDesktopNotifier_IsAvailable(t *testing.T) {
+	ln := NewLinuxDesktopNotifier(DesktopOptions{})
+
+	ln.lookPath = func(file string) (string, error) { return "/usr/bin/" + file, nil }
+	if !ln.IsAvailable() {
+		t.Error("expected notify-send to be available")
+	}
+
+	ln.lookPath = func(file string) (string, error) { return "", errors.New("not found") }
+	if ln.IsAvailable() {
+		t.Error("expected notify-send to be unavailable")
+	}
+}
+
+// notifySendHelp is the part of notify-send --help that matters for click to focus
+const notifySendHelp = "  -A, --action=[NAME=]Text...       Specifies the actions to display to the user.\n"
+
+func TestLinuxDesktopNotifier_FocusTerminal(t *testing.T) {
+	var started []string
+	clicked := make(chan struct{})
+	ln := NewLinuxDesktopNotifier(DesktopOptions{FocusTerminal: true, TmuxPane: "%3"})
+	ln.run = func(name string, args ...string) ([]byte, error) {
+		if strings.Join(args, " ") == "--help" {
+			return []byte(notifySendHelp), nil
+		}
+		return nil, nil
+	}
+	ln.start = func(name string, args ...string) (func() ([]byte, error), error) {
+		started = append([]string{name}, args...)
+		return func() ([]byte, error) {
+			<-clicked
+			return nil, nil
+		}, nil
+	}
+	defer close(clicked)
+
+	// Send returns without waiting for the notification to be clicked
+	if err := ln.Send(Notification{Title: "Claude Code: api", Message: "done"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"notify-send", "-u", "normal", "--action=focus=Focus terminal", "--wait", "--", "Claude Code: api", "done"}
+	if strings.Join(started, "|") != strings.Join(want, "|") {
+		t.Errorf("expected %v, got %v", want, started)
+	}
+}
+
+func TestLinuxDesktopNotifier_FocusTerminalStartError(t *testing.T) {
+	ln := NewLinuxDesktopNotifier(DesktopOptions{FocusTerminal: true})
+	ln.run = func(name string, args ...string) ([]byte, error) {
+		return []byte(notifySendHelp), nil
+	}
+	ln.start = func(name string, args ...string) (func() ([]byte, error), error) {
+		return nil, errors.New("fork/exec /usr/bin/notify-send: permission denied")
+	}
+
+	err := ln.Send(Notification{Title: "Test"})
+	if err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("expected start error to be returned, got %v", err)
+	}
+}
+
+func TestLinuxDesktopNotifier_FocusTerminalRetries(t *testing.T) {
+	ln := NewLinuxDesktopNotifier(DesktopOptions{FocusTerminal: true, Retries: 2})
+	ln.run = func(name string, args ...string) ([]byte, error) {
+		return []byte(notifySendHelp), nil
+	}
+	var delays []time.Duration
+	ln.sleep = func(d time.Duration) { delays = append(delays, d) }
+	starts := 0
+	ln.start = func(name string, args ...string) (func() ([]byte, error), error) {
+		starts++
+		if starts < 3 {
+			return nil, errors.New("resource temporarily unavailable")
+		}
+		return func() ([]byte, error) { return nil, nil }, nil
+	}
+
+	if err := ln.Send(Notification{Title: "Test"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if starts != 3 {
+		t.Errorf("expected 3 starts, got %d", starts)
+	}
+	if len(delays) != 2 || delays[1] != 2*delays[0] {
+		t.Errorf("expected 2 doubling delays, got %v", delays)
+	}
+}
+
+func TestLinuxDesktopNotifier_FocusTerminalWithoutActions(t *testing.T) {
+	var commands []string
+	ln := NewLinuxDesktopNotifier(DesktopOptions{FocusTerminal: true})
+	ln.run = func(name string, args ...string) ([]byte, error) {
+		commands = append(commands, name+" "+strings.Join(args, " "))
+		if strings.Join(args, " ") == "--help" {
+			return []byte("  -u, --urgency=LEVEL\n"), nil
+		}
+		return nil, nil
+	}
+	ln.start = func(name string, args ...string) (func() ([]byte, error), error) {
+		t.Fatal("notify-send should not be started with --action")
+		return nil, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := ln.Send(Notification{Title: "Test", Message: "done"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// --help is checked once, then notifications are sent without click to focus
+	want := []string{"notify-send --help", "notify-send -u normal -- Test done", "notify-send -u normal -- Test done"}
+	if strings.Join(commands, "|") != strings.Join(want, "|") {
+		t.Errorf("expected %q, got %q", want, commands)
+	}
+}
+
+func TestLinuxDesktopNotifier_FocusOnClick(t *testing.T) {
+	tests := []struct {
+		name     string
+		action   string
+		waitErr  error
+		windowID string
+		tmuxPane string
+		want     []string
+	}{
+		{
+			name:     "clicked in tmux",
+			action:   "focus\n",
+			windowID: "4194310",
+			tmuxPane: "%3",
+			want: []string{
+				"xdotool windowactivate 4194310",
+				"tmux switch-client -t %3",
+				"tmux select-window -t %3",
+				"tmux select-pane -t %3",
+			},
+		},
+		{
+			name:     "clicked outside tmux",
+			action:   "focus\n",
+			windowID: "4194310",
+			want:     []string{"xdotool windowactivate 4194310"},
+		},
+		{
+			name:     "dismissed",
+			action:   "",
+			windowID: "4194310",
+			tmuxPane: "%3",
+			want:     nil,
+		},
+		{
+			name:     "failed",
+			action:   "focus\n",
+			waitErr:  errors.New("exit status 1: cannot connect to dbus"),
+			windowID: "4194310",
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var commands []string
+			ln := NewLinuxDesktopNotifier(DesktopOptions{FocusTerminal: true, TmuxPane: tt.tmuxPane})
+			ln.windowID = tt.windowID
+			ln.run = func(name string, args ...string) ([]byte, error) {
+				commands = append(commands, name+" "+strings.Join(args, " "))
+				return nil, nil
+			}
+
+			ln.focusOnClick(func() ([]byte, error) { return []byte(tt.action), tt.waitErr })
+
+			if strings.Join(commands, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("expected %q, got %q", tt.want, commands)
+			}
+		})
+	}
+}
+
+func TestLinuxDesktopNotifier_Retries(t *testing.T) {
+	calls := 0
+	ln := NewLinuxDesktopNotifier(DesktopOptions{Retries: 3})
+	ln.sleep = func(time.Duration) {}
+	ln.run = func(name string, args ...string) ([]byte, error) {
+		calls++
+		if calls < 3 {
+			return []byte("cannot connect to dbus\n"), errors.New("exit status 1")
+		}
+		return nil, nil
+	}
+
+	if err := ln.Send(Notification{Title: "Test"}); err != nil {
+		t.Fatalf("expected success after retries, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected notify-send to run 3 times, ran %d", calls)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !darwin && !linux
+// +build !darwin,!linux
+
+package notification
+
+import "fmt"
+
+// NewDesktopNotifier creates the native desktop notifier for this platform
+func NewDesktopNotifier(opts DesktopOptions) (Notifier, error) {
+	return nil, fmt.Errorf("desktop notifications are not supported on this platform")
+}
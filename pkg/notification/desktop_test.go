@@ -0,0 +1,112 @@
+package notification
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTerminalBundleID(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{
+			name: "bundle identifier from launching app",
+			env:  map[string]string{"__CFBundleIdentifier": "net.kovidgoyal.kitty", "TERM_PROGRAM": "iTerm.app"},
+			want: "net.kovidgoyal.kitty",
+		},
+		{
+			name: "iTerm",
+			env:  map[string]string{"TERM_PROGRAM": "iTerm.app"},
+			want: "com.googlecode.iterm2",
+		},
+		{
+			name: "Terminal",
+			env:  map[string]string{"TERM_PROGRAM": "Apple_Terminal"},
+			want: "com.apple.Terminal",
+		},
+		{
+			name: "unknown terminal",
+			env:  map[string]string{"TERM_PROGRAM": "tmux"},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			if got := terminalBundleID(getenv); got != tt.want {
+				t.Errorf("terminalBundleID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunWithRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		retries    int
+		failures   int
+		wantErr    bool
+		wantCalls  int
+		wantSleeps []time.Duration
+	}{
+		{
+			name:      "succeeds first time",
+			retries:   2,
+			failures:  0,
+			wantCalls: 1,
+		},
+		{
+			name:       "succeeds after retries",
+			retries:    2,
+			failures:   2,
+			wantCalls:  3,
+			wantSleeps: []time.Duration{250 * time.Millisecond, 500 * time.Millisecond},
+		},
+		{
+			name:       "gives up after retries",
+			retries:    1,
+			failures:   3,
+			wantErr:    true,
+			wantCalls:  2,
+			wantSleeps: []time.Duration{250 * time.Millisecond},
+		},
+		{
+			name:      "no retries",
+			retries:   0,
+			failures:  1,
+			wantErr:   true,
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			run := func(name string, args ...string) ([]byte, error) {
+				calls++
+				if calls <= tt.failures {
+					return []byte("dbus not ready"), errors.New("exit status 1")
+				}
+				return nil, nil
+			}
+			var sleeps []time.Duration
+			sleep := func(d time.Duration) { sleeps = append(sleeps, d) }
+
+			_, err := runWithRetries(run, sleep, tt.retries, "notify-send", "Test")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("runWithRetries() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("expected %d calls, got %d", tt.wantCalls, calls)
+			}
+			if fmt.Sprint(sleeps) != fmt.Sprint(tt.wantSleeps) {
+				t.Errorf("expected sleeps %v, got %v", tt.wantSleeps, sleeps)
+			}
+		})
+	}
+}
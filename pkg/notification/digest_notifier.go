@@ -0,0 +1,120 @@
+package notification
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// digestSamples is how many messages are quoted in a digest
+const digestSamples = 3
+
+// digestPattern is the pattern of the summary notification itself
+const digestPattern = "digest"
+
+// DigestNotifier wraps another notifier and holds notifications back, sending one
+// summary with the count per pattern and a few sample messages on every Flush.
+// Notifications with a pass-through pattern are sent straight away.
+type DigestNotifier struct {
+	underlying  Notifier
+	passThrough map[string]bool
+
+	mu      sync.Mutex
+	counts  map[string]int
+	order   []string
+	samples []string
+	title   string
+	total   int
+}
+
+// NewDigestNotifier creates a new digest notifier that sends notifications with
+// the passThrough patterns straight away
+func NewDigestNotifier(underlying Notifier, passThrough ...string) *DigestNotifier {
+	dn := &DigestNotifier{
+		underlying:  underlying,
+		passThrough: make(map[string]bool, len(passThrough)),
+		counts:      make(map[string]int),
+	}
+	for _, pattern := range passThrough {
+		dn.passThrough[pattern] = true
+	}
+	return dn
+}
+
+// Holds reports whether notifications with pattern are held back for the digest
+func (dn *DigestNotifier) Holds(pattern string) bool {
+	return !dn.passThrough[pattern]
+}
+
+// Send implements the Notifier interface, adding the notification to the next digest
+func (dn *DigestNotifier) Send(notification Notification) error {
+	if !dn.Holds(notification.Pattern) {
+		return dn.underlying.Send(notification)
+	}
+
+	dn.mu.Lock()
+	defer dn.mu.Unlock()
+
+	pattern := notification.Pattern
+	if pattern == "" {
+		pattern = "other"
+	}
+	if dn.counts[pattern] == 0 {
+		dn.order = append(dn.order, pattern)
+	}
+	dn.counts[pattern]++
+	dn.total++
+
+	if len(dn.samples) < digestSamples && notification.Message != "" {
+		dn.samples = append(dn.samples, notification.Message)
+	}
+	dn.title = notification.Title
+
+	return nil
+}
+
+// Flush sends the digest of everything held back since the last flush, if anything was
+func (dn *DigestNotifier) Flush(now time.Time) error {
+	dn.mu.Lock()
+	if dn.total == 0 {
+		dn.mu.Unlock()
+		return nil
+	}
+
+	counts := make([]string, 0, len(dn.order))
+	for _, pattern := range dn.order {
+		counts = append(counts, fmt.Sprintf("%s ×%d", pattern, dn.counts[pattern]))
+	}
+	message := fmt.Sprintf("%d notification(s): %s", dn.total, strings.Join(counts, ", "))
+	for _, sample := range dn.samples {
+		message += "\n- " + sample
+	}
+
+	digest := Notification{
+		Title:   dn.title,
+		Message: message,
+		Time:    now,
+		Pattern: digestPattern,
+	}
+
+	dn.counts = make(map[string]int)
+	dn.order = nil
+	dn.samples = nil
+	dn.total = 0
+	dn.mu.Unlock()
+
+	return dn.underlying.Send(digest)
+}
+
+// Run flushes a digest on every tick until stop is closed
+func (dn *DigestNotifier) Run(ticks <-chan time.Time, stop <-chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticks:
+			_ = dn.Flush(now)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package notification
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDigestNotifier_SuppressesIndividualSends(t *testing.T) {
+	mock := &testNotifier{}
+	dn := NewDigestNotifier(mock)
+
+	for i := 0; i < 3; i++ {
+		if err := dn.Send(Notification{Title: "Claude Code: api", Message: "bell", Pattern: "bell"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if got := len(mock.getNotifications()); got != 0 {
+		t.Errorf("expected no notifications before a flush, got %d", got)
+	}
+}
+
+func TestDigestNotifier_PassThrough(t *testing.T) {
+	mock := &testNotifier{}
+	dn := NewDigestNotifier(mock, "heartbeat", "exit")
+
+	_ = dn.Send(Notification{Title: "Claude Code: api", Message: "bell", Pattern: "bell"})
+	_ = dn.Send(Notification{Title: "Claude Code: api", Message: "Still running", Pattern: "heartbeat"})
+	_ = dn.Send(Notification{Title: "Claude exited", Message: "Claude exited (code 0)", Pattern: "exit"})
+
+	notifications := mock.getNotifications()
+	if len(notifications) != 2 || notifications[0].Pattern != "heartbeat" || notifications[1].Pattern != "exit" {
+		t.Fatalf("expected heartbeat and exit to be sent straight away, got %v", notifications)
+	}
+
+	_ = dn.Flush(time.Now())
+	notifications = mock.getNotifications()
+	if len(notifications) != 3 || notifications[2].Message != "1 notification(s): bell ×1\n- bell" {
+		t.Errorf("expected a digest of the bell only, got %v", notifications)
+	}
+}
+
+func TestDigestNotifier_Flush(t *testing.T) {
+	mock := &testNotifier{}
+	dn := NewDigestNotifier(mock)
+
+	_ = dn.Send(Notification{Title: "Claude Code: api", Message: "Claude needs your input", Pattern: "bell"})
+	_ = dn.Send(Notification{Title: "Claude Code: api", Message: "No activity for 30s", Pattern: "backstop"})
+	_ = dn.Send(Notification{Title: "Claude Code: api", Message: "Claude needs your input", Pattern: "bell"})
+	_ = dn.Send(Notification{Title: "Claude Code: api - Fixing tests", Message: "Claude may be stuck", Pattern: "hang"})
+	_ = dn.Send(Notification{Title: "Claude Code: api", Message: "not sampled", Pattern: "bell"})
+
+	now := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	if err := dn.Flush(now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	notifications := mock.getNotifications()
+	if len(notifications) != 1 {
+		t.Fatalf("expected 1 digest, got %d", len(notifications))
+	}
+
+	digest := notifications[0]
+	wantMessage := strings.Join([]string{
+		"5 notification(s): bell ×3, backstop ×1, hang ×1",
+		"- Claude needs your input",
+		"- No activity for 30s",
+		"- Claude needs your input",
+	}, "\n")
+	if digest.Message != wantMessage {
+		t.Errorf("expected message %q, got %q", wantMessage, digest.Message)
+	}
+	if digest.Title != "Claude Code: api" {
+		t.Errorf("expected the latest title, got %q", digest.Title)
+	}
+	if digest.Pattern != "digest" || !digest.Time.Equal(now) {
+		t.Errorf("unexpected digest pattern %q or time %v", digest.Pattern, digest.Time)
+	}
+
+	// Nothing new since the last digest
+	if err := dn.Flush(now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := len(mock.getNotifications()); got != 1 {
+		t.Errorf("expected an empty flush to send nothing, got %d notifications", got)
+	}
+}
+
+func TestDigestNotifier_Run(t *testing.T) {
+	mock := &testNotifier{}
+	dn := NewDigestNotifier(mock)
+
+	ticks := make(chan time.Time)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		dn.Run(ticks, stop)
+		close(done)
+	}()
+
+	_ = dn.Send(Notification{Message: "first", Pattern: "bell"})
+	ticks <- time.Now()
+	// An empty interval sends nothing
+	ticks <- time.Now()
+	_ = dn.Send(Notification{Message: "second", Pattern: "bell"})
+	ticks <- time.Now()
+
+	close(stop)
+	<-done
+
+	notifications := mock.getNotifications()
+	if len(notifications) != 2 {
+		t.Fatalf("expected 2 digests, got %d", len(notifications))
+	}
+	for i, want := range []string{"first", "second"} {
+		if !strings.HasSuffix(notifications[i].Message, "- "+want) {
+			t.Errorf("digest %d: expected sample %q, got %q", i, want, notifications[i].Message)
+		}
+	}
+}
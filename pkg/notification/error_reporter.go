@@ -0,0 +1,78 @@
+package notification
+
+import (
+	"sync"
+	"time"
+)
+
+// internalErrorPriority keeps internal error reports from buzzing the phone
+const internalErrorPriority = 2
+
+// ErrorReporter sends a low priority notification for serious internal errors of the
+// wrapper itself, which would otherwise only be printed to stderr. Each distinct error
+// is reported once, and errors raised while a report is being sent are dropped, so a
+// failing notifier can't set off a loop of reports about itself.
+type ErrorReporter struct {
+	notifier Notifier
+
+	mu       sync.Mutex
+	sending  bool
+	reported map[string]bool
+}
+
+// NewErrorReporter creates a new error reporter that sends its reports to notifier
+func NewErrorReporter(notifier Notifier) *ErrorReporter {
+	return &ErrorReporter{
+		notifier: notifier,
+		reported: make(map[string]bool),
+	}
+}
+
+// Report sends a notification about err
+func (er *ErrorReporter) Report(err error) {
+	message := err.Error()
+
+	er.mu.Lock()
+	if er.sending || er.reported[message] {
+		er.mu.Unlock()
+		return
+	}
+	er.sending = true
+	er.reported[message] = true
+	er.mu.Unlock()
+
+	_ = er.notifier.Send(Notification{
+		Title:    "claude-code-ntfy internal error",
+		Message:  message,
+		Time:     time.Now(),
+		Pattern:  "internal_error",
+		Priority: internalErrorPriority,
+	})
+
+	er.mu.Lock()
+	er.sending = false
+	er.mu.Unlock()
+}
+
+// ReportFailures wraps a notifier so that its send failures are reported
+func (er *ErrorReporter) ReportFailures(underlying Notifier) Notifier {
+	return &failureReportingNotifier{
+		underlying: underlying,
+		reporter:   er,
+	}
+}
+
+// failureReportingNotifier forwards notifications and reports the ones that fail
+type failureReportingNotifier struct {
+	underlying Notifier
+	reporter   *ErrorReporter
+}
+
+// Send implements the Notifier interface
+func (fn *failureReportingNotifier) Send(notification Notification) error {
+	err := fn.underlying.Send(notification)
+	if err != nil {
+		fn.reporter.Report(err)
+	}
+	return err
+}
@@ -0,0 +1,77 @@
+package notification
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+// attemptNotifier counts every send, including failed ones
+type attemptNotifier struct {
+	mu       sync.Mutex
+	attempts int
+	err      error
+}
+
+func (an *attemptNotifier) Send(notification Notification) error {
+	an.mu.Lock()
+	defer an.mu.Unlock()
+	an.attempts++
+	return an.err
+}
+
+func TestErrorReporter_Report(t *testing.T) {
+	mock := &testNotifier{}
+	reporter := NewErrorReporter(mock)
+
+	reporter.Report(errors.New("I/O error: read /dev/ptmx: input/output error"))
+	// The same error again is not reported twice
+	reporter.Report(errors.New("I/O error: read /dev/ptmx: input/output error"))
+	reporter.Report(errors.New("failed to stop claude"))
+
+	notifications := mock.getNotifications()
+	if len(notifications) != 2 {
+		t.Fatalf("expected 2 reports, got %d", len(notifications))
+	}
+
+	n := notifications[0]
+	if n.Title != "claude-code-ntfy internal error" || n.Message != "I/O error: read /dev/ptmx: input/output error" {
+		t.Errorf("unexpected report %q: %q", n.Title, n.Message)
+	}
+	if n.Pattern != "internal_error" || n.Priority != internalErrorPriority {
+		t.Errorf("expected a low priority internal_error report, got %q at priority %d", n.Pattern, n.Priority)
+	}
+}
+
+func TestErrorReporter_ReportFailures(t *testing.T) {
+	primary := &attemptNotifier{err: errors.New("ntfy server returned status 502")}
+	secondary := &testNotifier{}
+	reporter := NewErrorReporter(secondary)
+
+	notifier := reporter.ReportFailures(primary)
+	if err := notifier.Send(Notification{Title: "Test"}); err == nil {
+		t.Fatal("expected the primary notifier's error")
+	}
+
+	notifications := secondary.getNotifications()
+	if len(notifications) != 1 || notifications[0].Message != "ntfy server returned status 502" {
+		t.Errorf("expected the failure to be reported to the secondary notifier, got %v", notifications)
+	}
+}
+
+func TestErrorReporter_NoLoop(t *testing.T) {
+	// Reports go through the same failing notifier whose failures are reported
+	failing := &attemptNotifier{err: errors.New("ntfy server returned status 502")}
+	reporter := NewErrorReporter(nil)
+	notifier := reporter.ReportFailures(failing)
+	reporter.notifier = notifier
+
+	if err := notifier.Send(Notification{Title: "Test"}); err == nil {
+		t.Fatal("expected the notifier's error")
+	}
+
+	// The notification itself, then one attempt to report its failure
+	if failing.attempts != 2 {
+		t.Errorf("expected 2 send attempts, got %d", failing.attempts)
+	}
+}
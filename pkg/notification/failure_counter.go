@@ -0,0 +1,36 @@
+package notification
+
+import "sync"
+
+// FailureCounter wraps another notifier and counts the notifications that failed to send
+type FailureCounter struct {
+	underlying Notifier
+
+	mu       sync.Mutex
+	failures int
+}
+
+// NewFailureCounter creates a new failure counter
+func NewFailureCounter(underlying Notifier) *FailureCounter {
+	return &FailureCounter{
+		underlying: underlying,
+	}
+}
+
+// Send implements the Notifier interface
+func (fc *FailureCounter) Send(notification Notification) error {
+	err := fc.underlying.Send(notification)
+	if err != nil {
+		fc.mu.Lock()
+		fc.failures++
+		fc.mu.Unlock()
+	}
+	return err
+}
+
+// Failures returns how many notifications have failed to send
+func (fc *FailureCounter) Failures() int {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return fc.failures
+}
@@ -0,0 +1,28 @@
+package notification
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFailureCounter(t *testing.T) {
+	mock := &testNotifier{}
+	fc := NewFailureCounter(mock)
+
+	if err := fc.Send(Notification{Title: "ok"}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if fc.Failures() != 0 {
+		t.Errorf("Failures() = %d, want 0", fc.Failures())
+	}
+
+	mock.sendError = errors.New("ntfy down")
+	for i := 0; i < 2; i++ {
+		if err := fc.Send(Notification{Title: "fails"}); err == nil {
+			t.Error("expected the send error to be returned")
+		}
+	}
+	if fc.Failures() != 2 {
+		t.Errorf("Failures() = %d, want 2", fc.Failures())
+	}
+}
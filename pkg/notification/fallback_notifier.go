@@ -0,0 +1,31 @@
+package notification
+
+import "errors"
+
+// FallbackNotifier sends to a fallback notifier only when the primary fails
+type FallbackNotifier struct {
+	primary  Notifier
+	fallback Notifier
+}
+
+// NewFallbackNotifier creates a new fallback notifier
+func NewFallbackNotifier(primary, fallback Notifier) *FallbackNotifier {
+	return &FallbackNotifier{
+		primary:  primary,
+		fallback: fallback,
+	}
+}
+
+// Send implements the Notifier interface.
+// A notification the fallback delivered counts as sent; if both fail, both errors are returned.
+func (fn *FallbackNotifier) Send(notification Notification) error {
+	primaryErr := fn.primary.Send(notification)
+	if primaryErr == nil {
+		return nil
+	}
+
+	if err := fn.fallback.Send(notification); err != nil {
+		return errors.Join(primaryErr, err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package notification
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFallbackNotifier(t *testing.T) {
+	primaryErr := errors.New("ntfy down")
+	fallbackErr := errors.New("no display")
+
+	tests := []struct {
+		name         string
+		primaryErr   error
+		fallbackErr  error
+		wantFallback bool
+		wantErrs     []error
+	}{
+		{
+			name:         "primary succeeds",
+			wantFallback: false,
+		},
+		{
+			name:         "primary fails",
+			primaryErr:   primaryErr,
+			wantFallback: true,
+		},
+		{
+			name:         "both fail",
+			primaryErr:   primaryErr,
+			fallbackErr:  fallbackErr,
+			wantFallback: true,
+			wantErrs:     []error{primaryErr, fallbackErr},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := &testNotifier{sendError: tt.primaryErr}
+			fallback := &testNotifier{sendError: tt.fallbackErr}
+
+			fn := NewFallbackNotifier(primary, fallback)
+			err := fn.Send(Notification{Title: "Test"})
+
+			// A failing test notifier records nothing, so a failed fallback shows up in the error instead
+			if tt.fallbackErr == nil {
+				if got := len(fallback.getNotifications()) == 1; got != tt.wantFallback {
+					t.Errorf("fallback invoked = %v, want %v", got, tt.wantFallback)
+				}
+			}
+			if len(tt.wantErrs) == 0 && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			for _, want := range tt.wantErrs {
+				if !errors.Is(err, want) {
+					t.Errorf("expected error to include %v, got %v", want, err)
+				}
+			}
+		})
+	}
+}
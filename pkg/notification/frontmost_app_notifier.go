@@ -0,0 +1,51 @@
+package notification
+
+import (
+	"os"
+	"strings"
+)
+
+// FrontmostAppNotifier wraps another notifier and drops notifications while the
+// terminal Claude runs in is the frontmost app on macOS, since the user is looking at it
+type FrontmostAppNotifier struct {
+	underlying Notifier
+	bundleID   string
+	run        DesktopRunner
+}
+
+// NewFrontmostAppNotifier creates a new frontmost app aware notifier for the terminal we run in
+func NewFrontmostAppNotifier(underlying Notifier) *FrontmostAppNotifier {
+	return &FrontmostAppNotifier{
+		underlying: underlying,
+		bundleID:   terminalBundleID(os.Getenv),
+		run:        runDesktopCommand,
+	}
+}
+
+// Send implements the Notifier interface
+func (fn *FrontmostAppNotifier) Send(notification Notification) error {
+	if fn.bundleID != "" && fn.frontmostApp() == fn.bundleID {
+		return nil
+	}
+
+	return fn.underlying.Send(notification)
+}
+
+// frontmostApp returns the bundle id of the frontmost app, or "" if it can't be found
+func (fn *FrontmostAppNotifier) frontmostApp() string {
+	asn, err := fn.run("lsappinfo", "front")
+	if err != nil {
+		return ""
+	}
+
+	// Prints e.g. "CFBundleIdentifier"="com.googlecode.iterm2"
+	output, err := fn.run("lsappinfo", "info", "-only", "bundleid", strings.TrimSpace(string(asn)))
+	if err != nil {
+		return ""
+	}
+	_, value, found := strings.Cut(strings.TrimSpace(string(output)), "=")
+	if !found {
+		return ""
+	}
+	return strings.Trim(value, `"`)
+}
@@ -0,0 +1,84 @@
+package notification
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFrontmostAppNotifier(t *testing.T) {
+	tests := []struct {
+		name      string
+		bundleID  string
+		frontmost string
+		runErr    error
+		wantSent  bool
+	}{
+		{
+			name:      "terminal is frontmost",
+			bundleID:  "com.googlecode.iterm2",
+			frontmost: "\"CFBundleIdentifier\"=\"com.googlecode.iterm2\"\n",
+			wantSent:  false,
+		},
+		{
+			name:      "another app is frontmost",
+			bundleID:  "com.googlecode.iterm2",
+			frontmost: "\"CFBundleIdentifier\"=\"com.apple.Safari\"\n",
+			wantSent:  true,
+		},
+		{
+			name:     "lsappinfo unavailable",
+			bundleID: "com.googlecode.iterm2",
+			runErr:   errors.New("executable file not found"),
+			wantSent: true,
+		},
+		{
+			name:      "unexpected output",
+			bundleID:  "com.googlecode.iterm2",
+			frontmost: "\n",
+			wantSent:  true,
+		},
+		{
+			name:      "unknown terminal",
+			bundleID:  "",
+			frontmost: "\"CFBundleIdentifier\"=\"\"\n",
+			wantSent:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &testNotifier{}
+			fn := NewFrontmostAppNotifier(mock)
+			fn.bundleID = tt.bundleID
+
+			var commands []string
+			fn.run = func(name string, args ...string) ([]byte, error) {
+				commands = append(commands, name+" "+strings.Join(args, " "))
+				if tt.runErr != nil {
+					return nil, tt.runErr
+				}
+				if len(args) == 1 && args[0] == "front" {
+					return []byte("ASN:0x0-0x1a01a:\n"), nil
+				}
+				return []byte(tt.frontmost), nil
+			}
+
+			if err := fn.Send(Notification{Title: "Test"}); err != nil {
+				t.Fatalf("Send failed: %v", err)
+			}
+
+			sent := len(mock.getNotifications()) == 1
+			if sent != tt.wantSent {
+				t.Errorf("expected sent=%v, got %v", tt.wantSent, sent)
+			}
+
+			if tt.frontmost != "" && tt.bundleID != "" {
+				want := "lsappinfo front|lsappinfo info -only bundleid ASN:0x0-0x1a01a:"
+				if strings.Join(commands, "|") != want {
+					t.Errorf("expected commands %q, got %q", want, commands)
+				}
+			}
+		})
+	}
+}
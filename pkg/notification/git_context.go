@@ -0,0 +1,137 @@
+package notification
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GitInfo identifies the repository and branch a directory belongs to
+type GitInfo struct {
+	Repo   string
+	Branch string
+}
+
+// Label returns the context label for notifications, e.g. "api@main"
+func (gi GitInfo) Label() string {
+	if gi.Branch == "" {
+		return gi.Repo
+	}
+	return gi.Repo + "@" + gi.Branch
+}
+
+// ReadGitInfo finds the git repository containing dir by reading .git directly,
+// without running git. It reports false when dir is not inside a repository.
+func ReadGitInfo(dir string) (GitInfo, bool) {
+	top, gitDir, ok := findGitDir(dir)
+	if !ok {
+		return GitInfo{}, false
+	}
+
+	info := GitInfo{
+		Repo:   repoFromRemote(commonGitDir(gitDir)),
+		Branch: branchFromHead(gitDir),
+	}
+	if info.Repo == "" {
+		info.Repo = filepath.Base(top)
+	}
+	return info, true
+}
+
+// findGitDir walks up from dir to the working tree top and its git directory.
+// In worktrees and submodules .git is a file pointing at the real git directory.
+func findGitDir(dir string) (string, string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", "", false
+	}
+
+	for {
+		dotGit := filepath.Join(dir, ".git")
+		if fi, err := os.Stat(dotGit); err == nil {
+			if fi.IsDir() {
+				return dir, dotGit, true
+			}
+			if data, err := os.ReadFile(dotGit); err == nil {
+				if gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: "); ok {
+					if !filepath.IsAbs(gitDir) {
+						gitDir = filepath.Join(dir, gitDir)
+					}
+					return dir, gitDir, true
+				}
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", "", false
+		}
+		dir = parent
+	}
+}
+
+// commonGitDir returns the git directory shared by all worktrees, which holds the config
+func commonGitDir(gitDir string) string {
+	data, err := os.ReadFile(filepath.Join(gitDir, "commondir"))
+	if err != nil {
+		return gitDir
+	}
+	common := strings.TrimSpace(string(data))
+	if !filepath.IsAbs(common) {
+		common = filepath.Join(gitDir, common)
+	}
+	return common
+}
+
+// branchFromHead returns the checked out branch, or the short commit when HEAD is detached
+func branchFromHead(gitDir string) string {
+	data, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return ""
+	}
+
+	head := strings.TrimSpace(string(data))
+	if ref, ok := strings.CutPrefix(head, "ref: "); ok {
+		return strings.TrimPrefix(ref, "refs/heads/")
+	}
+	if len(head) > 7 {
+		return head[:7]
+	}
+	return head
+}
+
+// repoFromRemote returns the repository name from the origin remote's URL
+func repoFromRemote(gitDir string) string {
+	f, err := os.Open(filepath.Join(gitDir, "config"))
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = f.Close() }()
+
+	inOrigin := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inOrigin = line == `[remote "origin"]`
+			continue
+		}
+		if !inOrigin {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(key) != "url" {
+			continue
+		}
+
+		// Handles https://host/owner/repo.git as well as git@host:owner/repo.git
+		url := strings.TrimSuffix(strings.TrimSuffix(strings.TrimSpace(value), "/"), ".git")
+		if i := strings.LastIndexAny(url, "/:"); i >= 0 {
+			url = url[i+1:]
+		}
+		return url
+	}
+	return ""
+}
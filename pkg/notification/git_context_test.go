@@ -0,0 +1,103 @@
+package notification
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFile creates a file and any missing parent directories
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func TestReadGitInfo(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  func(t *testing.T, root string) string
+		want   GitInfo
+		wantOK bool
+	}{
+		{
+			name: "branch and https remote",
+			setup: func(t *testing.T, root string) string {
+				writeFile(t, filepath.Join(root, "checkout", ".git", "HEAD"), "ref: refs/heads/feature/login\n")
+				writeFile(t, filepath.Join(root, "checkout", ".git", "config"),
+					"[core]\n\tbare = false\n[remote \"origin\"]\n\turl = https://github.com/acme/api.git\n")
+				return filepath.Join(root, "checkout")
+			},
+			want:   GitInfo{Repo: "api", Branch: "feature/login"},
+			wantOK: true,
+		},
+		{
+			name: "ssh remote from a subdirectory",
+			setup: func(t *testing.T, root string) string {
+				writeFile(t, filepath.Join(root, "checkout", ".git", "HEAD"), "ref: refs/heads/main\n")
+				writeFile(t, filepath.Join(root, "checkout", ".git", "config"),
+					"[remote \"upstream\"]\n\turl = git@github.com:other/fork.git\n[remote \"origin\"]\n\turl = git@github.com:acme/web.git\n")
+				writeFile(t, filepath.Join(root, "checkout", "src", "app", "main.go"), "package main\n")
+				return filepath.Join(root, "checkout", "src", "app")
+			},
+			want:   GitInfo{Repo: "web", Branch: "main"},
+			wantOK: true,
+		},
+		{
+			name: "worktree falls back to shared config",
+			setup: func(t *testing.T, root string) string {
+				common := filepath.Join(root, "main", ".git")
+				writeFile(t, filepath.Join(common, "config"), "[remote \"origin\"]\n\turl = https://github.com/acme/api\n")
+				writeFile(t, filepath.Join(common, "worktrees", "hotfix", "HEAD"), "ref: refs/heads/hotfix\n")
+				writeFile(t, filepath.Join(common, "worktrees", "hotfix", "commondir"), "../..\n")
+				writeFile(t, filepath.Join(root, "api-hotfix", ".git"), "gitdir: "+filepath.Join(common, "worktrees", "hotfix")+"\n")
+				return filepath.Join(root, "api-hotfix")
+			},
+			want:   GitInfo{Repo: "api", Branch: "hotfix"},
+			wantOK: true,
+		},
+		{
+			name: "detached head without remote",
+			setup: func(t *testing.T, root string) string {
+				writeFile(t, filepath.Join(root, "scratch", ".git", "HEAD"), "0123456789abcdef0123456789abcdef01234567\n")
+				return filepath.Join(root, "scratch")
+			},
+			want:   GitInfo{Repo: "scratch", Branch: "0123456"},
+			wantOK: true,
+		},
+		{
+			name: "not a repository",
+			setup: func(t *testing.T, root string) string {
+				return root
+			},
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := tt.setup(t, t.TempDir())
+
+			got, ok := ReadGitInfo(dir)
+			if ok != tt.wantOK {
+				t.Fatalf("ReadGitInfo() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("ReadGitInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitInfo_Label(t *testing.T) {
+	if got := (GitInfo{Repo: "api", Branch: "main"}).Label(); got != "api@main" {
+		t.Errorf("Label() = %q, want %q", got, "api@main")
+	}
+	if got := (GitInfo{Repo: "api"}).Label(); got != "api" {
+		t.Errorf("Label() = %q, want %q", got, "api")
+	}
+}
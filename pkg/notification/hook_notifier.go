@@ -0,0 +1,183 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+)
+
+// CommandRunner runs a shell command with extra environment variables
+type CommandRunner func(command string, env []string) error
+
+// runShellCommand runs command through sh with env added to our environment
+func runShellCommand(command string, env []string) error {
+	// #nosec G204 - Hook commands come from the user's own configuration
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Env = append(os.Environ(), env...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, output)
+	}
+	return nil
+}
+
+// hookEnv returns the environment describing a notification to a hook command
+func hookEnv(notification Notification) []string {
+	return []string{
+		"CLAUDE_NOTIFY_TITLE=" + notification.Title,
+		"CLAUDE_NOTIFY_MESSAGE=" + notification.Message,
+		"CLAUDE_NOTIFY_PATTERN=" + notification.Pattern,
+	}
+}
+
+// PostHookNotifier wraps another notifier and runs a command after each notification is sent
+type PostHookNotifier struct {
+	underlying Notifier
+	command    string
+	run        CommandRunner
+}
+
+// NewPostHookNotifier creates a new post-hook notifier
+func NewPostHookNotifier(underlying Notifier, command string) *PostHookNotifier {
+	return &PostHookNotifier{
+		underlying: underlying,
+		command:    command,
+		run:        runShellCommand,
+	}
+}
+
+// Send implements the Notifier interface
+func (pn *PostHookNotifier) Send(notification Notification) error {
+	if err := pn.underlying.Send(notification); err != nil {
+		return err
+	}
+
+	// Run the hook in the background so a slow hook never blocks notifications
+	go func() {
+		if err := pn.run(pn.command, hookEnv(notification)); err != nil {
+			slog.Warn("post_hook failed", "err", err)
+		}
+	}()
+
+	return nil
+}
+
+// errHookVeto is returned by a FilterRunner when the hook exited non-zero
+var errHookVeto = errors.New("vetoed by pre_hook")
+
+// FilterRunner runs a shell command with input on stdin and returns its stdout
+type FilterRunner func(command string, input []byte) ([]byte, error)
+
+// runShellFilter runs command through sh, feeding it input on stdin.
+// A non-zero exit is reported as errHookVeto, except when the shell couldn't run
+// the command (126 or 127) or it was killed by a signal, which are hook errors.
+func runShellFilter(command string, input []byte) ([]byte, error) {
+	// #nosec G204 - Hook commands come from the user's own configuration
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if stderr.Len() > 0 {
+		slog.Warn("pre_hook wrote to stderr", "output", strings.TrimSpace(stderr.String()))
+	}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && isVetoExitCode(exitErr.ExitCode()) {
+			return nil, fmt.Errorf("%w (exit status %d)", errHookVeto, exitErr.ExitCode())
+		}
+		return nil, err
+	}
+	return output, nil
+}
+
+// isVetoExitCode reports whether a hook's exit code means it vetoed the notification.
+// The shell exits 126 or 127 when the command can't be run, and ExitCode is -1 after a signal.
+func isVetoExitCode(code int) bool {
+	return code > 0 && code != 126 && code != 127
+}
+
+// hookPayload is the JSON form of a notification exchanged with a pre-hook
+type hookPayload struct {
+	Title    string    `json:"title"`
+	Message  string    `json:"message"`
+	Pattern  string    `json:"pattern"`
+	Priority int       `json:"priority,omitempty"`
+	Tags     []string  `json:"tags,omitempty"`
+	Click    string    `json:"click,omitempty"`
+	Time     time.Time `json:"time"`
+}
+
+// PreHookNotifier wraps another notifier and lets a command modify or veto each notification.
+// The command receives the notification as JSON on stdin. It may print a modified
+// notification, print nothing to send it unchanged, or exit non-zero to suppress it.
+type PreHookNotifier struct {
+	underlying Notifier
+	command    string
+	run        FilterRunner
+}
+
+// NewPreHookNotifier creates a new pre-hook notifier
+func NewPreHookNotifier(underlying Notifier, command string) *PreHookNotifier {
+	return &PreHookNotifier{
+		underlying: underlying,
+		command:    command,
+		run:        runShellFilter,
+	}
+}
+
+// Send implements the Notifier interface
+func (pn *PreHookNotifier) Send(notification Notification) error {
+	payload := hookPayload{
+		Title:    notification.Title,
+		Message:  notification.Message,
+		Pattern:  notification.Pattern,
+		Priority: notification.Priority,
+		Tags:     notification.Tags,
+		Click:    notification.Click,
+		Time:     notification.Time,
+	}
+	input, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	output, err := pn.run(pn.command, input)
+	if errors.Is(err, errHookVeto) {
+		slog.Info("pre_hook vetoed a notification", "title", notification.Title, "pattern", notification.Pattern, "err", err)
+		return nil
+	}
+	if err != nil {
+		// A broken hook shouldn't cost the user their notification
+		slog.Warn("pre_hook failed, sending the notification unchanged", "err", err)
+		return pn.underlying.Send(notification)
+	}
+
+	if len(bytes.TrimSpace(output)) == 0 {
+		return pn.underlying.Send(notification)
+	}
+
+	// Fields the hook leaves out keep their values. Decoding tags reuses the
+	// slice, so give it a copy rather than the caller's tags.
+	modified := payload
+	modified.Tags = slices.Clone(payload.Tags)
+	if err := json.Unmarshal(output, &modified); err != nil {
+		slog.Warn("pre_hook returned invalid JSON, sending the notification unchanged", "err", err)
+		return pn.underlying.Send(notification)
+	}
+
+	notification.Title = modified.Title
+	notification.Message = modified.Message
+	notification.Pattern = modified.Pattern
+	notification.Priority = modified.Priority
+	notification.Tags = modified.Tags
+	notification.Click = modified.Click
+	return pn.underlying.Send(notification)
+}
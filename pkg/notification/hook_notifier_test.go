@@ -0,0 +1,262 @@
+package notification
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// hookCall records a single hook invocation
+type hookCall struct {
+	command string
+	env     []string
+}
+
+func TestPostHookNotifier(t *testing.T) {
+	mock := &testNotifier{}
+	calls := make(chan hookCall, 2)
+
+	pn := NewPostHookNotifier(mock, "notify-hook")
+	pn.run = func(command string, env []string) error {
+		calls <- hookCall{command: command, env: env}
+		return nil
+	}
+
+	notifications := []Notification{
+		{Title: "Claude needs attention", Message: "No activity detected", Pattern: "backstop"},
+		{Title: "Claude Code Session Started", Message: "Working directory: /tmp", Pattern: "startup"},
+	}
+
+	for _, n := range notifications {
+		if err := pn.Send(n); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+
+		select {
+		case call := <-calls:
+			if call.command != "notify-hook" {
+				t.Errorf("command = %q, want notify-hook", call.command)
+			}
+			want := []string{
+				"CLAUDE_NOTIFY_TITLE=" + n.Title,
+				"CLAUDE_NOTIFY_MESSAGE=" + n.Message,
+				"CLAUDE_NOTIFY_PATTERN=" + n.Pattern,
+			}
+			if len(call.env) != len(want) {
+				t.Fatalf("env = %v, want %v", call.env, want)
+			}
+			for i := range want {
+				if call.env[i] != want[i] {
+					t.Errorf("env[%d] = %q, want %q", i, call.env[i], want[i])
+				}
+			}
+		case <-time.After(time.Second):
+			t.Fatal("post hook was not run")
+		}
+	}
+
+	if len(mock.getNotifications()) != 2 {
+		t.Errorf("expected 2 notifications forwarded, got %d", len(mock.getNotifications()))
+	}
+}
+
+func TestPostHookNotifier_HookFailureDoesNotFailSend(t *testing.T) {
+	mock := &testNotifier{}
+	done := make(chan struct{})
+
+	pn := NewPostHookNotifier(mock, "false")
+	pn.run = func(command string, env []string) error {
+		defer close(done)
+		return errors.New("exit status 1")
+	}
+
+	if err := pn.Send(Notification{Title: "Test"}); err != nil {
+		t.Errorf("expected hook failure not to fail Send, got %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("post hook was not run")
+	}
+}
+
+func TestPostHookNotifier_SkippedWhenSendFails(t *testing.T) {
+	mock := &testNotifier{sendError: errors.New("ntfy down")}
+	pn := NewPostHookNotifier(mock, "notify-hook")
+	pn.run = func(command string, env []string) error {
+		t.Error("hook should not run when the notification was not sent")
+		return nil
+	}
+
+	if err := pn.Send(Notification{Title: "Test"}); err == nil {
+		t.Error("expected send error to be returned")
+	}
+	time.Sleep(20 * time.Millisecond)
+}
+
+func TestPreHookNotifier_PartialOutput(t *testing.T) {
+	mock := &testNotifier{}
+	pn := NewPreHookNotifier(mock, "filter-hook")
+	pn.run = func(command string, input []byte) ([]byte, error) {
+		return []byte(`{"message":"Come back!"}`), nil
+	}
+
+	original := Notification{
+		Title:    "Claude needs attention",
+		Message:  "No activity detected",
+		Pattern:  "backstop",
+		Priority: 4,
+		Tags:     []string{"warning"},
+		Click:    "https://example.com",
+	}
+	if err := pn.Send(original); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	sent := mock.getNotifications()
+	if len(sent) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(sent))
+	}
+	want := original
+	want.Message = "Come back!"
+	if sent[0].Title != want.Title || sent[0].Message != want.Message || sent[0].Pattern != want.Pattern ||
+		sent[0].Priority != want.Priority || sent[0].Click != want.Click ||
+		strings.Join(sent[0].Tags, ",") != strings.Join(want.Tags, ",") {
+		t.Errorf("expected only the message to change, got %+v", sent[0])
+	}
+}
+
+func TestPreHookNotifier(t *testing.T) {
+	original := Notification{
+		Title:   "Claude needs attention",
+		Message: "No activity detected",
+		Pattern: "backstop",
+	}
+
+	tests := []struct {
+		name      string
+		output    string
+		runErr    error
+		wantSent  bool
+		wantTitle string
+		wantMsg   string
+		wantTags  []string
+	}{
+		{
+			name:      "modify",
+			output:    `{"title":"Claude needs attention","message":"Come back!","pattern":"backstop"}`,
+			wantSent:  true,
+			wantTitle: "Claude needs attention",
+			wantMsg:   "Come back!",
+		},
+		{
+			name:      "add tags",
+			output:    `{"title":"Claude needs attention","message":"No activity detected","pattern":"backstop","tags":["warning"]}`,
+			wantSent:  true,
+			wantTitle: original.Title,
+			wantMsg:   original.Message,
+			wantTags:  []string{"warning"},
+		},
+		{
+			name:      "pass through",
+			output:    "",
+			wantSent:  true,
+			wantTitle: original.Title,
+			wantMsg:   original.Message,
+		},
+		{
+			name:     "veto",
+			runErr:   errHookVeto,
+			wantSent: false,
+		},
+		{
+			name:      "hook failure sends unchanged",
+			runErr:    errors.New("sh: not found"),
+			wantSent:  true,
+			wantTitle: original.Title,
+			wantMsg:   original.Message,
+		},
+		{
+			name:      "invalid output sends unchanged",
+			output:    "not json",
+			wantSent:  true,
+			wantTitle: original.Title,
+			wantMsg:   original.Message,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &testNotifier{}
+			pn := NewPreHookNotifier(mock, "filter-hook")
+
+			var gotInput string
+			pn.run = func(command string, input []byte) ([]byte, error) {
+				gotInput = string(input)
+				return []byte(tt.output), tt.runErr
+			}
+
+			if err := pn.Send(original); err != nil {
+				t.Fatalf("Send failed: %v", err)
+			}
+
+			if !strings.Contains(gotInput, `"message":"No activity detected"`) {
+				t.Errorf("hook input missing message: %s", gotInput)
+			}
+
+			sent := mock.getNotifications()
+			if !tt.wantSent {
+				if len(sent) != 0 {
+					t.Errorf("expected notification to be vetoed, got %v", sent)
+				}
+				return
+			}
+			if len(sent) != 1 {
+				t.Fatalf("expected 1 notification, got %d", len(sent))
+			}
+			if sent[0].Title != tt.wantTitle {
+				t.Errorf("Title = %q, want %q", sent[0].Title, tt.wantTitle)
+			}
+			if sent[0].Message != tt.wantMsg {
+				t.Errorf("Message = %q, want %q", sent[0].Message, tt.wantMsg)
+			}
+			if strings.Join(sent[0].Tags, ",") != strings.Join(tt.wantTags, ",") {
+				t.Errorf("Tags = %v, want %v", sent[0].Tags, tt.wantTags)
+			}
+		})
+	}
+}
+
+func TestRunShellFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  string
+		wantOut  string
+		wantVeto bool
+		wantErr  bool
+	}{
+		{name: "output", command: "cat", wantOut: "input"},
+		{name: "veto", command: "exit 1", wantVeto: true},
+		{name: "command not found", command: "no-such-pre-hook-command", wantErr: true},
+		{name: "not executable", command: "exit 126", wantErr: true},
+		{name: "killed by a signal", command: "kill -TERM $$", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := runShellFilter(tt.command, []byte("input"))
+
+			if got := errors.Is(err, errHookVeto); got != tt.wantVeto {
+				t.Errorf("veto = %v, want %v (err %v)", got, tt.wantVeto, err)
+			}
+			if got := err != nil && !errors.Is(err, errHookVeto); got != tt.wantErr {
+				t.Errorf("hook error = %v, want %v (err %v)", got, tt.wantErr, err)
+			}
+			if string(output) != tt.wantOut {
+				t.Errorf("output = %q, want %q", output, tt.wantOut)
+			}
+		})
+	}
+}
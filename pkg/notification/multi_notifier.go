@@ -0,0 +1,27 @@
+package notification
+
+import "errors"
+
+// MultiNotifier sends every notification to several notifiers
+type MultiNotifier struct {
+	notifiers []Notifier
+}
+
+// NewMultiNotifier creates a new fan-out notifier
+func NewMultiNotifier(notifiers ...Notifier) *MultiNotifier {
+	return &MultiNotifier{
+		notifiers: notifiers,
+	}
+}
+
+// Send implements the Notifier interface.
+// Every notifier is tried even if an earlier one fails; the failures are joined.
+func (mn *MultiNotifier) Send(notification Notification) error {
+	var errs []error
+	for _, notifier := range mn.notifiers {
+		if err := notifier.Send(notification); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
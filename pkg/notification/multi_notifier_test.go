@@ -0,0 +1,41 @@
+package notification
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMultiNotifier(t *testing.T) {
+	first := &testNotifier{}
+	failing := &testNotifier{sendError: errors.New("ntfy down")}
+	last := &testNotifier{}
+
+	mn := NewMultiNotifier(first, failing, last)
+	err := mn.Send(Notification{Title: "Test"})
+
+	if !errors.Is(err, failing.sendError) {
+		t.Errorf("expected the failure to be returned, got %v", err)
+	}
+	if len(first.getNotifications()) != 1 || len(last.getNotifications()) != 1 {
+		t.Error("expected a failing notifier not to stop the others")
+	}
+}
+
+func TestMultiNotifier_JoinsErrors(t *testing.T) {
+	errA := errors.New("ntfy down")
+	errB := errors.New("webhook down")
+
+	mn := NewMultiNotifier(&testNotifier{sendError: errA}, &testNotifier{sendError: errB})
+	err := mn.Send(Notification{Title: "Test"})
+
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("expected both errors to be joined, got %v", err)
+	}
+}
+
+func TestMultiNotifier_AllSucceed(t *testing.T) {
+	mn := NewMultiNotifier(&testNotifier{}, &testNotifier{})
+	if err := mn.Send(Notification{Title: "Test"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
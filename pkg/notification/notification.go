@@ -9,6 +9,12 @@ type Notification struct {
 	Message string
 	Time    time.Time
 	Pattern string
+	// Priority is the ntfy priority from 1 (min) to 5 (max); 0 uses the server default
+	Priority int
+	// Tags are extra ntfy tags; names matching an emoji short code are shown as that emoji
+	Tags []string
+	// Click is a URL opened when the notification is tapped
+	Click string
 }
 
 // Notifier sends notifications.
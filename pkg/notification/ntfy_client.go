@@ -4,7 +4,12 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"math/rand/v2"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -13,17 +18,108 @@ type NtfyClient struct {
 	server     string
 	topic      string
 	httpClient *http.Client
+	markdown   MarkdownMode
+
+	username string
+	password string
+
+	retryAttempts  int
+	retryBaseDelay time.Duration
+	sleep          func(time.Duration)
+
+	deliveryLog   *slog.Logger
+	mu            sync.Mutex
+	lastMessageID string
+}
+
+// MarkdownMode controls whether ntfy renders messages as markdown
+type MarkdownMode string
+
+// Markdown modes
+const (
+	MarkdownOff  MarkdownMode = "off"
+	MarkdownOn   MarkdownMode = "on"
+	MarkdownAuto MarkdownMode = "auto"
+)
+
+// NtfyOption configures an NtfyClient
+type NtfyOption func(*NtfyClient)
+
+// WithDisableKeepAlives makes every send open a fresh connection instead of reusing one
+func WithDisableKeepAlives() NtfyOption {
+	return func(c *NtfyClient) {
+		c.transport().DisableKeepAlives = true
+	}
+}
+
+// WithTimeout limits how long a single request may take, including connecting and reading the response
+func WithTimeout(timeout time.Duration) NtfyOption {
+	return func(c *NtfyClient) {
+		c.httpClient.Timeout = timeout
+	}
+}
+
+// WithBasicAuth authenticates to the server with HTTP Basic Auth
+func WithBasicAuth(username, password string) NtfyOption {
+	return func(c *NtfyClient) {
+		c.username = username
+		c.password = password
+	}
 }
 
-// NewNtfyClient creates a new ntfy.sh client
-func NewNtfyClient(server, topic string) *NtfyClient {
-	return &NtfyClient{
+// WithProxy sends requests through the given proxy instead of the one from HTTP_PROXY/HTTPS_PROXY
+func WithProxy(proxyURL *url.URL) NtfyOption {
+	return func(c *NtfyClient) {
+		c.transport().Proxy = http.ProxyURL(proxyURL)
+	}
+}
+
+// WithMarkdown sets whether messages are rendered as markdown; MarkdownAuto decides per message
+func WithMarkdown(mode MarkdownMode) NtfyOption {
+	return func(c *NtfyClient) {
+		c.markdown = mode
+	}
+}
+
+// WithRetry retries failed sends up to attempts more times, doubling the delay
+// from baseDelay each time up to a minute. Only network errors, 5xx and 429 responses are retried.
+func WithRetry(attempts int, baseDelay time.Duration) NtfyOption {
+	return func(c *NtfyClient) {
+		c.retryAttempts = attempts
+		c.retryBaseDelay = baseDelay
+	}
+}
+
+// WithDeliveryLog logs the ntfy message id of every delivered notification at debug level
+func WithDeliveryLog(logger *slog.Logger) NtfyOption {
+	return func(c *NtfyClient) {
+		c.deliveryLog = logger
+	}
+}
+
+// NewNtfyClient creates a new ntfy.sh client.
+// Without WithProxy, proxies are taken from the environment.
+func NewNtfyClient(server, topic string, opts ...NtfyOption) *NtfyClient {
+	c := &NtfyClient{
 		server: server,
 		topic:  topic,
 		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout:   10 * time.Second,
+			Transport: http.DefaultTransport.(*http.Transport).Clone(),
 		},
+		sleep: time.Sleep,
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// transport returns the client's HTTP transport for options to configure
+func (c *NtfyClient) transport() *http.Transport {
+	return c.httpClient.Transport.(*http.Transport)
 }
 
 // Send sends a notification to ntfy.sh
@@ -37,7 +133,16 @@ func (c *NtfyClient) Send(notification Notification) error {
 		"topic":   c.topic,
 		"title":   notification.Title,
 		"message": notification.Message,
-		"tags":    []string{"claude-code", notification.Pattern},
+		"tags":    append([]string{"claude-code", notification.Pattern}, notification.Tags...),
+	}
+	if notification.Priority != 0 {
+		payload["priority"] = notification.Priority
+	}
+	if notification.Click != "" {
+		payload["click"] = notification.Click
+	}
+	if c.markdown == MarkdownOn || (c.markdown == MarkdownAuto && looksLikeMarkdown(notification.Message)) {
+		payload["markdown"] = true
 	}
 
 	jsonData, err := json.Marshal(payload)
@@ -45,26 +150,119 @@ func (c *NtfyClient) Send(notification Notification) error {
 		return fmt.Errorf("failed to marshal notification: %w", err)
 	}
 
+	for attempt := 0; ; attempt++ {
+		id, retryable, err := c.post(jsonData)
+		if err == nil {
+			c.recordDelivery(notification, id)
+			return nil
+		}
+		if !retryable || attempt >= c.retryAttempts {
+			return err
+		}
+		c.sleep(c.retryDelay(attempt))
+	}
+}
+
+// LastMessageID returns the id ntfy assigned to the most recently delivered notification
+func (c *NtfyClient) LastMessageID() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastMessageID
+}
+
+// recordDelivery remembers the message id and writes it to the delivery log
+func (c *NtfyClient) recordDelivery(notification Notification, id string) {
+	c.mu.Lock()
+	c.lastMessageID = id
+	c.mu.Unlock()
+
+	if c.deliveryLog != nil && id != "" {
+		c.deliveryLog.Debug("notification delivered", "title", notification.Title, "pattern", notification.Pattern, "id", id)
+	}
+}
+
+// post publishes the payload once, returning the message id ntfy assigned it
+// and, on failure, whether the failure is worth retrying
+func (c *NtfyClient) post(jsonData []byte) (string, bool, error) {
 	// Create the request
 	url := fmt.Sprintf("%s/", c.server)
 	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return "", false, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	if c.username != "" && c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
 
 	// Send the request
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to send notification: %w", err)
+		return "", true, fmt.Errorf("failed to send notification: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	// Check response
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("ntfy returned status %d", resp.StatusCode)
+		retryable := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return "", retryable, fmt.Errorf("ntfy returned status %d", resp.StatusCode)
+	}
+
+	// The id is only used for tracing, so a body we can't read isn't an error
+	var published struct {
+		ID string `json:"id"`
 	}
+	_ = json.NewDecoder(resp.Body).Decode(&published)
 
-	return nil
+	return published.ID, false, nil
+}
+
+// maxRetryDelay caps the doubling backoff between retries, before jitter
+const maxRetryDelay = time.Minute
+
+// retryDelay returns the backoff before retry number attempt+1, with up to 50% jitter
+func (c *NtfyClient) retryDelay(attempt int) time.Duration {
+	// Check before shifting, so a large attempt can't overflow the delay
+	delay := maxRetryDelay
+	if c.retryBaseDelay <= maxRetryDelay>>attempt {
+		delay = c.retryBaseDelay << attempt
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay + rand.N(delay/2+1)
+}
+
+// looksLikeMarkdown reports whether a message contains a code fence,
+// or spans several lines and has list items or headings
+func looksLikeMarkdown(message string) bool {
+	if strings.Contains(message, "```") {
+		return true
+	}
+
+	lines := strings.Split(message, "\n")
+	if len(lines) < 2 {
+		return false
+	}
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") ||
+			strings.HasPrefix(line, "# ") || strings.HasPrefix(line, "## ") ||
+			startsWithNumberedItem(line) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// startsWithNumberedItem reports whether line starts like "1. item"
+func startsWithNumberedItem(line string) bool {
+	digits := 0
+	for digits < len(line) && line[digits] >= '0' && line[digits] <= '9' {
+		digits++
+	}
+	return digits > 0 && strings.HasPrefix(line[digits:], ". ")
 }
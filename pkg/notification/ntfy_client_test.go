@@ -1,12 +1,18 @@
 package notification
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -149,6 +155,322 @@ func TestNtfyClient_Send(t *testing.T) {
 	}
 }
 
+func TestNtfyClient_Priority(t *testing.T) {
+	tests := []struct {
+		name         string
+		priority     int
+		wantPriority interface{}
+	}{
+		{"default priority omitted", 0, nil},
+		{"low priority", 2, float64(2)},
+		{"max priority", 5, float64(5)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				_ = json.Unmarshal(body, &payload)
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client := NewNtfyClient(server.URL, "test-topic")
+			if err := client.Send(Notification{Title: "Test", Priority: tt.priority}); err != nil {
+				t.Fatalf("Send() error = %v", err)
+			}
+
+			if got := payload["priority"]; got != tt.wantPriority {
+				t.Errorf("priority = %v, want %v", got, tt.wantPriority)
+			}
+		})
+	}
+}
+
+func TestNtfyClient_Tags(t *testing.T) {
+	var payload struct {
+		Tags []string `json:"tags"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &payload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewNtfyClient(server.URL, "test-topic")
+	if err := client.Send(Notification{Title: "Test", Pattern: "backstop", Tags: []string{"warning", "skull"}}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	want := []string{"claude-code", "backstop", "warning", "skull"}
+	if strings.Join(payload.Tags, ",") != strings.Join(want, ",") {
+		t.Errorf("tags = %v, want %v", payload.Tags, want)
+	}
+}
+
+func TestNtfyClient_Click(t *testing.T) {
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &payload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewNtfyClient(server.URL, "test-topic")
+
+	if err := client.Send(Notification{Title: "Test"}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if _, ok := payload["click"]; ok {
+		t.Error("expected no click field when Click is empty")
+	}
+
+	if err := client.Send(Notification{Title: "Test", Click: "https://ci.example.com"}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if payload["click"] != "https://ci.example.com" {
+		t.Errorf("click = %v, want https://ci.example.com", payload["click"])
+	}
+}
+
+func TestNtfyClient_Markdown(t *testing.T) {
+	tests := []struct {
+		name         string
+		mode         MarkdownMode
+		message      string
+		wantMarkdown bool
+	}{
+		{"off by default", "", "- one\n- two", false},
+		{"always on", MarkdownOn, "plain", true},
+		{"explicitly off", MarkdownOff, "```\ncode\n```", false},
+		{"auto code fence", MarkdownAuto, "Output:\n```\ngo test ./...\n```", true},
+		{"auto bullet list", MarkdownAuto, "Changes:\n- config\n- tests", true},
+		{"auto numbered list", MarkdownAuto, "Steps:\n1. build\n2. test", true},
+		{"auto plain single line", MarkdownAuto, "No activity detected", false},
+		{"auto plain multi line", MarkdownAuto, "Working directory: /tmp\nStarted at 10:00", false},
+		{"auto dash without list", MarkdownAuto, "- not a list on its own", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				_ = json.Unmarshal(body, &payload)
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client := NewNtfyClient(server.URL, "test-topic", WithMarkdown(tt.mode))
+			if err := client.Send(Notification{Title: "Test", Message: tt.message}); err != nil {
+				t.Fatalf("Send() error = %v", err)
+			}
+
+			if got := payload["markdown"] == true; got != tt.wantMarkdown {
+				t.Errorf("markdown = %v, want %v", payload["markdown"], tt.wantMarkdown)
+			}
+		})
+	}
+}
+
+func TestNtfyClient_Retry(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		attempts     int
+		wantErr      bool
+		wantRequests int
+	}{
+		{"recovers after transient errors", []int{503, 503, 200}, 3, false, 3},
+		{"retries rate limiting", []int{429, 200}, 3, false, 2},
+		{"gives up after configured retries", []int{503, 503, 503, 503}, 2, true, 3},
+		{"does not retry client errors", []int{401, 200}, 3, true, 1},
+		{"does not retry by default", []int{503, 200}, 0, true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				status := tt.statuses[requests]
+				requests++
+				mu.Unlock()
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			client := NewNtfyClient(server.URL, "test-topic", WithRetry(tt.attempts, 100*time.Millisecond))
+			var delays []time.Duration
+			client.sleep = func(d time.Duration) { delays = append(delays, d) }
+
+			err := client.Send(Notification{Title: "Test"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Send() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if requests != tt.wantRequests {
+				t.Errorf("made %d requests, want %d", requests, tt.wantRequests)
+			}
+
+			// Each delay doubles the base delay, plus at most 50% jitter
+			for i, d := range delays {
+				lower := 100 * time.Millisecond << i
+				if d < lower || d > lower+lower/2 {
+					t.Errorf("delay %d = %v, want between %v and %v", i, d, lower, lower+lower/2)
+				}
+			}
+		})
+	}
+}
+
+func TestNtfyClient_Proxy(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request bypassed the proxy")
+	}))
+	defer target.Close()
+	targetURL, _ := url.Parse(target.URL)
+
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A proxied request carries the absolute target URL
+		proxied = append(proxied, r.URL.Host)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+	proxyURL, _ := url.Parse(proxy.URL)
+
+	client := NewNtfyClient(target.URL, "test-topic", WithProxy(proxyURL))
+	if err := client.Send(Notification{Title: "Test"}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if len(proxied) != 1 || proxied[0] != targetURL.Host {
+		t.Errorf("proxy saw requests for %v, want [%s]", proxied, targetURL.Host)
+	}
+}
+
+func TestNtfyClient_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-time.After(2 * time.Second):
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewNtfyClient(server.URL, "test-topic", WithTimeout(50*time.Millisecond))
+
+	start := time.Now()
+	err := client.Send(Notification{Title: "Test"})
+	if err == nil {
+		t.Fatal("expected a timeout error")
+	}
+
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Errorf("expected a timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Send took %v, expected it to give up after the timeout", elapsed)
+	}
+}
+
+func TestNtfyClient_BasicAuth(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []NtfyOption
+		wantAuth bool
+	}{
+		{"no credentials", nil, false},
+		{"username and password", []NtfyOption{WithBasicAuth("phil", "secret")}, true},
+		{"username only", []NtfyOption{WithBasicAuth("phil", "")}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var authHeader string
+			var username, password string
+			var ok bool
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				authHeader = r.Header.Get("Authorization")
+				username, password, ok = r.BasicAuth()
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client := NewNtfyClient(server.URL, "test-topic", tt.opts...)
+			if err := client.Send(Notification{Title: "Test"}); err != nil {
+				t.Fatalf("Send() error = %v", err)
+			}
+
+			if !tt.wantAuth {
+				if authHeader != "" {
+					t.Errorf("expected no Authorization header, got %q", authHeader)
+				}
+				return
+			}
+			if !ok || username != "phil" || password != "secret" {
+				t.Errorf("BasicAuth() = %q, %q, %v, want phil, secret, true", username, password, ok)
+			}
+		})
+	}
+}
+
+func TestNtfyClient_MessageID(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantID  string
+		wantLog string
+	}{
+		{
+			name:    "id in response",
+			body:    `{"id":"test123","event":"message"}`,
+			wantID:  "test123",
+			wantLog: `level=DEBUG msg="notification delivered" title=Done pattern=backstop id=test123`,
+		},
+		{
+			name: "no id in response",
+			body: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+
+			var log bytes.Buffer
+			client := NewNtfyClient(server.URL, "test-topic", WithDeliveryLog(slog.New(slog.NewTextHandler(&log, &slog.HandlerOptions{Level: slog.LevelDebug}))))
+			if err := client.Send(Notification{Title: "Done", Pattern: "backstop"}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := client.LastMessageID(); got != tt.wantID {
+				t.Errorf("LastMessageID() = %q, want %q", got, tt.wantID)
+			}
+			if tt.wantLog == "" && log.Len() != 0 {
+				t.Errorf("expected no log output, got %q", log.String())
+			}
+			if !strings.Contains(log.String(), tt.wantLog) {
+				t.Errorf("expected log to contain %q, got %q", tt.wantLog, log.String())
+			}
+		})
+	}
+}
+
 func TestNtfyClient_SendNetworkError(t *testing.T) {
 	// Use invalid URL to simulate network error
 	client := NewNtfyClient("http://localhost:0", "test-topic")
@@ -270,3 +592,49 @@ func TestNewNtfyClient(t *testing.T) {
 		})
 	}
 }
+
+func TestNtfyClient_DisableKeepAlives(t *testing.T) {
+	var mu sync.Mutex
+	connections := 0
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			mu.Lock()
+			connections++
+			mu.Unlock()
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	client := NewNtfyClient(server.URL, "test-topic", WithDisableKeepAlives())
+	if !client.transport().DisableKeepAlives {
+		t.Fatal("expected keep-alives to be disabled on the transport")
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := client.Send(Notification{Title: "Test", Message: "Test"}); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if connections != 3 {
+		t.Errorf("expected a new connection per send, got %d connections for 3 sends", connections)
+	}
+}
+
+func TestNtfyClient_RetryDelayCap(t *testing.T) {
+	client := NewNtfyClient("https://ntfy.sh", "test-topic", WithRetry(100, time.Second))
+
+	for _, attempt := range []int{6, 7, 40, 63, 64, 99} {
+		d := client.retryDelay(attempt)
+		if d < maxRetryDelay || d > maxRetryDelay+maxRetryDelay/2 {
+			t.Errorf("retryDelay(%d) = %v, want between %v and %v", attempt, d, maxRetryDelay, maxRetryDelay+maxRetryDelay/2)
+		}
+	}
+}
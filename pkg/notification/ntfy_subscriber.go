@@ -0,0 +1,125 @@
+package notification
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ntfyEvent is a single event from the ntfy SSE stream
+type ntfyEvent struct {
+	ID      string   `json:"id"`
+	Time    int64    `json:"time"`
+	Event   string   `json:"event"`
+	Topic   string   `json:"topic"`
+	Title   string   `json:"title"`
+	Message string   `json:"message"`
+	Tags    []string `json:"tags"`
+}
+
+// NtfySubscriber streams messages published to an ntfy topic
+type NtfySubscriber struct {
+	server     string
+	topic      string
+	httpClient *http.Client
+	username   string
+	password   string
+}
+
+// NewNtfySubscriber creates a new ntfy topic subscriber.
+// It takes the same connection options as NtfyClient, such as auth and proxy.
+func NewNtfySubscriber(server, topic string, opts ...NtfyOption) *NtfySubscriber {
+	c := NewNtfyClient(server, topic, opts...)
+
+	// The stream stays open until cancelled, so the timeout only limits waiting for the server to answer
+	transport := c.transport()
+	transport.ResponseHeaderTimeout = c.httpClient.Timeout
+
+	return &NtfySubscriber{
+		server:     server,
+		topic:      topic,
+		httpClient: &http.Client{Transport: transport},
+		username:   c.username,
+		password:   c.password,
+	}
+}
+
+// Subscribe connects to the topic's SSE stream and writes each message to w
+// until the stream ends or ctx is cancelled
+func (s *NtfySubscriber) Subscribe(ctx context.Context, w io.Writer) error {
+	if s.topic == "" {
+		return fmt.Errorf("ntfy topic not configured")
+	}
+
+	url := fmt.Sprintf("%s/%s/sse", strings.TrimSuffix(s.server, "/"), s.topic)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if s.username != "" && s.password != "" {
+		req.SetBasicAuth(s.username, s.password)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("ntfy returned status %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			// Skip event names, ids and blank separators
+			continue
+		}
+
+		var event ntfyEvent
+		if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &event); err != nil {
+			return fmt.Errorf("failed to parse event: %w", err)
+		}
+
+		// Only print real messages, not open/keepalive events
+		if event.Event != "message" {
+			continue
+		}
+
+		if _, err := fmt.Fprintln(w, formatEvent(event)); err != nil {
+			return fmt.Errorf("failed to write message: %w", err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("stream error: %w", err)
+	}
+
+	return nil
+}
+
+// formatEvent renders a message event as a single line
+func formatEvent(event ntfyEvent) string {
+	var b strings.Builder
+	b.WriteString("[")
+	b.WriteString(time.Unix(event.Time, 0).Format("15:04:05"))
+	b.WriteString("] ")
+	if event.Title != "" {
+		b.WriteString(event.Title)
+		b.WriteString(": ")
+	}
+	b.WriteString(event.Message)
+	if len(event.Tags) > 0 {
+		b.WriteString(" (tags: ")
+		b.WriteString(strings.Join(event.Tags, ", "))
+		b.WriteString(")")
+	}
+	return b.String()
+}
@@ -0,0 +1,120 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNtfySubscriber_Subscribe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/test-topic/sse" {
+			t.Errorf("Path = %v, want /test-topic/sse", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprint(w, "event: open\n")
+		_, _ = fmt.Fprint(w, `data: {"id":"a1","time":1700000000,"event":"open","topic":"test-topic"}`+"\n\n")
+		_, _ = fmt.Fprint(w, `data: {"id":"b2","time":1700000001,"event":"message","topic":"test-topic","title":"Claude Code: project","message":"No activity detected","tags":["claude-code","backstop"]}`+"\n\n")
+		_, _ = fmt.Fprint(w, "event: keepalive\n")
+		_, _ = fmt.Fprint(w, `data: {"id":"c3","time":1700000002,"event":"keepalive","topic":"test-topic"}`+"\n\n")
+		_, _ = fmt.Fprint(w, `data: {"id":"d4","time":1700000003,"event":"message","topic":"test-topic","message":"plain"}`+"\n\n")
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	subscriber := NewNtfySubscriber(server.URL, "test-topic")
+	if err := subscriber.Subscribe(context.Background(), &out); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 printed messages, got %d: %q", len(lines), out.String())
+	}
+	if !strings.Contains(lines[0], "Claude Code: project: No activity detected (tags: claude-code, backstop)") {
+		t.Errorf("unexpected first line: %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], "] plain") {
+		t.Errorf("unexpected second line: %q", lines[1])
+	}
+}
+
+func TestNtfySubscriber_Options(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "alice" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.(http.Flusher).Flush()
+
+		// Outlast the timeout, which must not cut an open stream
+		time.Sleep(100 * time.Millisecond)
+		_, _ = fmt.Fprint(w, `data: {"id":"b2","time":1700000001,"event":"message","topic":"test-topic","message":"late"}`+"\n\n")
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	subscriber := NewNtfySubscriber(server.URL, "test-topic",
+		WithBasicAuth("alice", "secret"), WithTimeout(50*time.Millisecond))
+	if err := subscriber.Subscribe(context.Background(), &out); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if !strings.HasSuffix(strings.TrimSpace(out.String()), "] late") {
+		t.Errorf("expected the late message, got %q", out.String())
+	}
+}
+
+func TestNtfySubscriber_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		topic       string
+		handler     http.HandlerFunc
+		errContains string
+	}{
+		{
+			name:        "missing topic",
+			topic:       "",
+			handler:     func(w http.ResponseWriter, r *http.Request) {},
+			errContains: "topic not configured",
+		},
+		{
+			name:  "server error",
+			topic: "test-topic",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+			},
+			errContains: "ntfy returned status 403",
+		},
+		{
+			name:  "malformed event",
+			topic: "test-topic",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = fmt.Fprint(w, "data: {not json}\n\n")
+			},
+			errContains: "failed to parse event",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			subscriber := NewNtfySubscriber(server.URL, tt.topic)
+			err := subscriber.Subscribe(context.Background(), &bytes.Buffer{})
+			if err == nil {
+				t.Fatal("expected error but got none")
+			}
+			if !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("Error = %v, want to contain %v", err, tt.errContains)
+			}
+		})
+	}
+}
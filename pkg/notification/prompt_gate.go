@@ -0,0 +1,47 @@
+package notification
+
+import (
+	"bytes"
+	"sync"
+)
+
+// PromptGate wraps another notifier and drops notifications until the user submits
+// their first prompt, so Claude redrawing the screen while the first prompt is typed
+// can't trigger anything. The startup notification is always let through.
+type PromptGate struct {
+	underlying Notifier
+
+	mu   sync.Mutex
+	open bool
+}
+
+// NewPromptGate creates a new prompt gate, closed until the first prompt is submitted
+func NewPromptGate(underlying Notifier) *PromptGate {
+	return &PromptGate{
+		underlying: underlying,
+	}
+}
+
+// HandleInput watches stdin data and opens the gate once Enter is pressed
+func (pg *PromptGate) HandleInput(data []byte) {
+	if !bytes.ContainsAny(data, "\r\n") {
+		return
+	}
+
+	pg.mu.Lock()
+	defer pg.mu.Unlock()
+	pg.open = true
+}
+
+// Send implements the Notifier interface
+func (pg *PromptGate) Send(notification Notification) error {
+	pg.mu.Lock()
+	open := pg.open
+	pg.mu.Unlock()
+
+	if !open && notification.Pattern != "startup" {
+		return nil
+	}
+
+	return pg.underlying.Send(notification)
+}
@@ -0,0 +1,28 @@
+package notification
+
+import "testing"
+
+func TestPromptGate(t *testing.T) {
+	mock := &testNotifier{}
+	pg := NewPromptGate(mock)
+
+	// Startup is sent before any prompt could be typed
+	_ = pg.Send(Notification{Title: "Started", Pattern: "startup"})
+
+	// Typing the first prompt doesn't open the gate until it is submitted
+	pg.HandleInput([]byte("fix the fail"))
+	_ = pg.Send(Notification{Title: "Before prompt", Pattern: "backstop"})
+	pg.HandleInput([]byte("ing tests"))
+	_ = pg.Send(Notification{Title: "Still before prompt", Pattern: "backstop"})
+
+	pg.HandleInput([]byte("\r"))
+	_ = pg.Send(Notification{Title: "After prompt", Pattern: "backstop"})
+
+	sent := mock.getNotifications()
+	if len(sent) != 2 {
+		t.Fatalf("expected 2 notifications, got %d: %v", len(sent), sent)
+	}
+	if sent[0].Title != "Started" || sent[1].Title != "After prompt" {
+		t.Errorf("unexpected notifications: %q, %q", sent[0].Title, sent[1].Title)
+	}
+}
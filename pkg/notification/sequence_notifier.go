@@ -0,0 +1,34 @@
+package notification
+
+import (
+	"fmt"
+	"sync"
+)
+
+// SequenceNotifier wraps another notifier and appends an increasing number to every title,
+// so a gap in the sequence shows that a notification was lost
+type SequenceNotifier struct {
+	underlying Notifier
+
+	mu   sync.Mutex
+	next int
+}
+
+// NewSequenceNotifier creates a new sequence notifier starting at 1
+func NewSequenceNotifier(underlying Notifier) *SequenceNotifier {
+	return &SequenceNotifier{
+		underlying: underlying,
+		next:       1,
+	}
+}
+
+// Send implements the Notifier interface
+func (sn *SequenceNotifier) Send(notification Notification) error {
+	sn.mu.Lock()
+	seq := sn.next
+	sn.next++
+	sn.mu.Unlock()
+
+	notification.Title = fmt.Sprintf("%s #%d", notification.Title, seq)
+	return sn.underlying.Send(notification)
+}
@@ -0,0 +1,40 @@
+package notification
+
+import "testing"
+
+func TestSequenceNotifier(t *testing.T) {
+	mock := &testNotifier{}
+	sn := NewSequenceNotifier(mock)
+
+	for i := 0; i < 3; i++ {
+		if err := sn.Send(Notification{Title: "Claude Code: project", Message: "waiting"}); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+	}
+
+	want := []string{"Claude Code: project #1", "Claude Code: project #2", "Claude Code: project #3"}
+	sent := mock.getNotifications()
+	if len(sent) != len(want) {
+		t.Fatalf("expected %d notifications, got %d", len(want), len(sent))
+	}
+	for i, n := range sent {
+		if n.Title != want[i] {
+			t.Errorf("Title[%d] = %q, want %q", i, n.Title, want[i])
+		}
+		if n.Message != "waiting" {
+			t.Errorf("Message[%d] = %q, want it unchanged", i, n.Message)
+		}
+	}
+}
+
+func TestSequenceNotifier_PerInstance(t *testing.T) {
+	mock := &testNotifier{}
+
+	_ = NewSequenceNotifier(mock).Send(Notification{Title: "first session"})
+	_ = NewSequenceNotifier(mock).Send(Notification{Title: "second session"})
+
+	sent := mock.getNotifications()
+	if sent[0].Title != "first session #1" || sent[1].Title != "second session #1" {
+		t.Errorf("expected each notifier to start at 1, got %q and %q", sent[0].Title, sent[1].Title)
+	}
+}
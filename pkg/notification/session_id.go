@@ -0,0 +1,37 @@
+package notification
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// NewSessionID returns a short random id identifying one run of the wrapper
+func NewSessionID() string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+// SessionIDNotifier wraps another notifier and tags every notification with the session id
+type SessionIDNotifier struct {
+	underlying Notifier
+	tag        string
+}
+
+// NewSessionIDNotifier creates a new session id notifier
+func NewSessionIDNotifier(underlying Notifier, sessionID string) *SessionIDNotifier {
+	return &SessionIDNotifier{
+		underlying: underlying,
+		tag:        "session-" + sessionID,
+	}
+}
+
+// Send implements the Notifier interface
+func (sn *SessionIDNotifier) Send(notification Notification) error {
+	// Copy so we never append into a slice shared with the caller
+	tags := make([]string, 0, len(notification.Tags)+1)
+	notification.Tags = append(append(tags, notification.Tags...), sn.tag)
+	return sn.underlying.Send(notification)
+}
@@ -0,0 +1,39 @@
+package notification
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestNewSessionID(t *testing.T) {
+	first := NewSessionID()
+	second := NewSessionID()
+
+	if !regexp.MustCompile(`^[0-9a-f]{8}$`).MatchString(first) {
+		t.Errorf("session id %q is not 8 hex characters", first)
+	}
+	if first == second {
+		t.Errorf("expected different ids across runs, got %q twice", first)
+	}
+}
+
+func TestSessionIDNotifier(t *testing.T) {
+	mock := &testNotifier{}
+	sn := NewSessionIDNotifier(mock, "1a2b3c4d")
+
+	_ = sn.Send(Notification{Title: "first"})
+	_ = sn.Send(Notification{Title: "second", Tags: []string{"warning"}})
+
+	sent := mock.getNotifications()
+	if len(sent) != 2 {
+		t.Fatalf("expected 2 notifications, got %d", len(sent))
+	}
+	for _, n := range sent {
+		if len(n.Tags) == 0 || n.Tags[len(n.Tags)-1] != "session-1a2b3c4d" {
+			t.Errorf("%s: tags = %v, want session-1a2b3c4d last", n.Title, n.Tags)
+		}
+	}
+	if sent[1].Tags[0] != "warning" {
+		t.Errorf("expected existing tags to be kept, got %v", sent[1].Tags)
+	}
+}
@@ -0,0 +1,149 @@
+package notification
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// PatternStats holds the notification counts for a single pattern
+type PatternStats struct {
+	Triggered int
+	Sent      int
+	Failed    int
+	Digested  int
+}
+
+// Suppressed returns how many triggered notifications never reached the backend,
+// not counting the ones held back for a digest
+func (ps PatternStats) Suppressed() int {
+	return ps.Triggered - ps.Sent - ps.Failed - ps.Digested
+}
+
+// SessionStats counts notifications per pattern over a session.
+// Wrap the top of the notifier chain with CountTriggered and the backend with
+// CountDelivered; anything in between that drops a notification counts as suppressed.
+// A DigestNotifier in between is wrapped with CountDigested.
+type SessionStats struct {
+	mu     sync.Mutex
+	counts map[string]*PatternStats
+}
+
+// NewSessionStats creates an empty set of session statistics
+func NewSessionStats() *SessionStats {
+	return &SessionStats{
+		counts: make(map[string]*PatternStats),
+	}
+}
+
+// CountTriggered wraps a notifier so every notification passing through counts as triggered
+func (s *SessionStats) CountTriggered(underlying Notifier) Notifier {
+	return &statsNotifier{
+		underlying: underlying,
+		record: func(pattern string, err error) {
+			s.update(pattern, func(ps *PatternStats) { ps.Triggered++ })
+		},
+	}
+}
+
+// CountDelivered wraps a notifier so every notification passing through counts as sent or failed.
+// Digests aren't counted; the notifications in them were counted by CountDigested.
+func (s *SessionStats) CountDelivered(underlying Notifier) Notifier {
+	return &statsNotifier{
+		underlying: underlying,
+		record: func(pattern string, err error) {
+			if pattern == digestPattern {
+				return
+			}
+			s.update(pattern, func(ps *PatternStats) {
+				if err != nil {
+					ps.Failed++
+				} else {
+					ps.Sent++
+				}
+			})
+		},
+	}
+}
+
+// CountDigested wraps a digest notifier so every notification it holds back counts as digested
+func (s *SessionStats) CountDigested(digest *DigestNotifier) Notifier {
+	return &statsNotifier{
+		underlying: digest,
+		record: func(pattern string, err error) {
+			if err == nil && digest.Holds(pattern) {
+				s.update(pattern, func(ps *PatternStats) { ps.Digested++ })
+			}
+		},
+	}
+}
+
+// Snapshot returns a copy of the counts keyed by pattern
+func (s *SessionStats) Snapshot() map[string]PatternStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := make(map[string]PatternStats, len(s.counts))
+	for pattern, ps := range s.counts {
+		snapshot[pattern] = *ps
+	}
+	return snapshot
+}
+
+// WriteSummary writes one line per pattern, sorted by pattern name
+func (s *SessionStats) WriteSummary(w io.Writer) error {
+	snapshot := s.Snapshot()
+
+	patterns := make([]string, 0, len(snapshot))
+	for pattern := range snapshot {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	if _, err := fmt.Fprintln(w, "claude-code-ntfy: notification stats"); err != nil {
+		return err
+	}
+	if len(patterns) == 0 {
+		_, err := fmt.Fprintln(w, "  no notifications")
+		return err
+	}
+	for _, pattern := range patterns {
+		ps := snapshot[pattern]
+		var digested string
+		if ps.Digested > 0 {
+			digested = fmt.Sprintf(", %d digested", ps.Digested)
+		}
+		if _, err := fmt.Fprintf(w, "  %s: %d triggered, %d sent%s, %d failed, %d suppressed\n",
+			pattern, ps.Triggered, ps.Sent, digested, ps.Failed, ps.Suppressed()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// update applies fn to the counts for pattern
+func (s *SessionStats) update(pattern string, fn func(*PatternStats)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ps, ok := s.counts[pattern]
+	if !ok {
+		ps = &PatternStats{}
+		s.counts[pattern] = ps
+	}
+	fn(ps)
+}
+
+// statsNotifier forwards notifications and records the result of each send
+type statsNotifier struct {
+	underlying Notifier
+	record     func(pattern string, err error)
+}
+
+// Send implements the Notifier interface
+func (sn *statsNotifier) Send(notification Notification) error {
+	err := sn.underlying.Send(notification)
+	sn.record(notification.Pattern, err)
+	return err
+}
@@ -0,0 +1,121 @@
+package notification
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+// dropNotifier drops notifications for one pattern, like snooze or the prompt gate would
+type dropNotifier struct {
+	underlying Notifier
+	pattern    string
+}
+
+func (dn *dropNotifier) Send(notification Notification) error {
+	if notification.Pattern == dn.pattern {
+		return nil
+	}
+	return dn.underlying.Send(notification)
+}
+
+func TestSessionStats(t *testing.T) {
+	backend := &testNotifier{}
+	stats := NewSessionStats()
+
+	chain := stats.CountTriggered(&dropNotifier{
+		underlying: stats.CountDelivered(backend),
+		pattern:    "heartbeat",
+	})
+
+	_ = chain.Send(Notification{Pattern: "startup"})
+	_ = chain.Send(Notification{Pattern: "backstop"})
+	_ = chain.Send(Notification{Pattern: "backstop"})
+	_ = chain.Send(Notification{Pattern: "heartbeat"})
+
+	backend.sendError = errors.New("ntfy down")
+	_ = chain.Send(Notification{Pattern: "backstop"})
+
+	want := map[string]PatternStats{
+		"startup":   {Triggered: 1, Sent: 1},
+		"backstop":  {Triggered: 3, Sent: 2, Failed: 1},
+		"heartbeat": {Triggered: 1},
+	}
+
+	got := stats.Snapshot()
+	if len(got) != len(want) {
+		t.Fatalf("got stats for %d patterns, want %d: %v", len(got), len(want), got)
+	}
+	for pattern, ps := range want {
+		if got[pattern] != ps {
+			t.Errorf("%s: got %+v, want %+v", pattern, got[pattern], ps)
+		}
+	}
+	if got["heartbeat"].Suppressed() != 1 {
+		t.Errorf("heartbeat suppressed = %d, want 1", got["heartbeat"].Suppressed())
+	}
+
+	var buf bytes.Buffer
+	if err := stats.WriteSummary(&buf); err != nil {
+		t.Fatalf("WriteSummary failed: %v", err)
+	}
+	wantSummary := "claude-code-ntfy: notification stats\n" +
+		"  backstop: 3 triggered, 2 sent, 1 failed, 0 suppressed\n" +
+		"  heartbeat: 1 triggered, 0 sent, 0 failed, 1 suppressed\n" +
+		"  startup: 1 triggered, 1 sent, 0 failed, 0 suppressed\n"
+	if buf.String() != wantSummary {
+		t.Errorf("summary = %q, want %q", buf.String(), wantSummary)
+	}
+}
+
+func TestSessionStats_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewSessionStats().WriteSummary(&buf); err != nil {
+		t.Fatalf("WriteSummary failed: %v", err)
+	}
+	if buf.String() != "claude-code-ntfy: notification stats\n  no notifications\n" {
+		t.Errorf("unexpected summary: %q", buf.String())
+	}
+}
+
+func TestSessionStats_Digest(t *testing.T) {
+	backend := &testNotifier{}
+	stats := NewSessionStats()
+
+	digest := NewDigestNotifier(stats.CountDelivered(backend), "heartbeat")
+	chain := stats.CountTriggered(stats.CountDigested(digest))
+
+	_ = chain.Send(Notification{Pattern: "backstop"})
+	_ = chain.Send(Notification{Pattern: "backstop"})
+	_ = chain.Send(Notification{Pattern: "heartbeat"})
+	if err := digest.Flush(time.Now()); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	want := map[string]PatternStats{
+		"backstop":  {Triggered: 2, Digested: 2},
+		"heartbeat": {Triggered: 1, Sent: 1},
+	}
+
+	got := stats.Snapshot()
+	if len(got) != len(want) {
+		t.Fatalf("got stats for %d patterns, want %d: %v", len(got), len(want), got)
+	}
+	for pattern, ps := range want {
+		if got[pattern] != ps {
+			t.Errorf("%s: got %+v, want %+v", pattern, got[pattern], ps)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := stats.WriteSummary(&buf); err != nil {
+		t.Fatalf("WriteSummary failed: %v", err)
+	}
+	wantSummary := "claude-code-ntfy: notification stats\n" +
+		"  backstop: 2 triggered, 0 sent, 2 digested, 0 failed, 0 suppressed\n" +
+		"  heartbeat: 1 triggered, 1 sent, 0 failed, 0 suppressed\n"
+	if buf.String() != wantSummary {
+		t.Errorf("summary = %q, want %q", buf.String(), wantSummary)
+	}
+}
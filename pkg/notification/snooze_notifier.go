@@ -0,0 +1,74 @@
+package notification
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SnoozeNotifier wraps another notifier and drops notifications while a snooze is active.
+// The snooze expiry is read from a file on every send so a running session picks up
+// `claude-code-ntfy snooze` from another terminal.
+type SnoozeNotifier struct {
+	underlying Notifier
+	path       string
+	now        func() time.Time
+}
+
+// NewSnoozeNotifier creates a new snooze notifier reading the expiry from path
+func NewSnoozeNotifier(underlying Notifier, path string) *SnoozeNotifier {
+	return &SnoozeNotifier{
+		underlying: underlying,
+		path:       path,
+		now:        time.Now,
+	}
+}
+
+// Send implements the Notifier interface
+func (sn *SnoozeNotifier) Send(notification Notification) error {
+	// A missing or unreadable snooze file means we're not snoozed
+	if until, err := ReadSnooze(sn.path); err == nil && sn.now().Before(until) {
+		return nil
+	}
+
+	return sn.underlying.Send(notification)
+}
+
+// WriteSnooze records that notifications are snoozed until the given time
+func WriteSnooze(path string, until time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create snooze directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, []byte(until.Format(time.RFC3339)+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to write snooze file: %w", err)
+	}
+
+	return nil
+}
+
+// ReadSnooze returns the time notifications are snoozed until
+func ReadSnooze(path string) (time.Time, error) {
+	// #nosec G304 - The snooze path comes from trusted sources (standard state location)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	until, err := time.Parse(time.RFC3339, strings.TrimSpace(string(data)))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid snooze file: %w", err)
+	}
+
+	return until, nil
+}
+
+// ClearSnooze removes any active snooze
+func ClearSnooze(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove snooze file: %w", err)
+	}
+	return nil
+}
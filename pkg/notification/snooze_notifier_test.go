@@ -0,0 +1,92 @@
+package notification
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSnoozeNotifier(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "snooze")
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	mock := &testNotifier{}
+	sn := NewSnoozeNotifier(mock, path)
+	now := start
+	sn.now = func() time.Time { return now }
+
+	// Not snoozed without a snooze file
+	if err := sn.Send(Notification{Pattern: "before"}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if err := WriteSnooze(path, start.Add(30*time.Minute)); err != nil {
+		t.Fatalf("WriteSnooze failed: %v", err)
+	}
+
+	// Suppressed during the snooze window
+	now = start.Add(10 * time.Minute)
+	if err := sn.Send(Notification{Pattern: "during"}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	// Resumes automatically once the snooze expires
+	now = start.Add(31 * time.Minute)
+	if err := sn.Send(Notification{Pattern: "after"}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	notifications := mock.getNotifications()
+	if len(notifications) != 2 {
+		t.Fatalf("expected 2 notifications, got %d", len(notifications))
+	}
+	if notifications[0].Pattern != "before" || notifications[1].Pattern != "after" {
+		t.Errorf("unexpected notifications delivered: %+v", notifications)
+	}
+}
+
+func TestSnoozeNotifier_ClearSnooze(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snooze")
+	if err := WriteSnooze(path, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("WriteSnooze failed: %v", err)
+	}
+
+	mock := &testNotifier{}
+	sn := NewSnoozeNotifier(mock, path)
+
+	_ = sn.Send(Notification{})
+	if len(mock.getNotifications()) != 0 {
+		t.Fatal("expected notification to be snoozed")
+	}
+
+	if err := ClearSnooze(path); err != nil {
+		t.Fatalf("ClearSnooze failed: %v", err)
+	}
+	// Clearing twice is fine
+	if err := ClearSnooze(path); err != nil {
+		t.Fatalf("second ClearSnooze failed: %v", err)
+	}
+
+	_ = sn.Send(Notification{})
+	if len(mock.getNotifications()) != 1 {
+		t.Error("expected notification after clearing the snooze")
+	}
+}
+
+func TestSnoozeNotifier_InvalidFileIsIgnored(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snooze")
+	if err := os.WriteFile(path, []byte("not a time"), 0600); err != nil {
+		t.Fatalf("failed to write snooze file: %v", err)
+	}
+
+	if _, err := ReadSnooze(path); err == nil {
+		t.Error("expected error for invalid snooze file")
+	}
+
+	mock := &testNotifier{}
+	_ = NewSnoozeNotifier(mock, path).Send(Notification{})
+	if len(mock.getNotifications()) != 1 {
+		t.Error("expected invalid snooze file to be ignored")
+	}
+}
@@ -0,0 +1,75 @@
+package notification
+
+import (
+	"sync"
+	"time"
+)
+
+// StartupCoalescer wraps another notifier and holds the startup notification back
+// for a short window, dropping it if another notification is sent in the meantime.
+// Notifications with a kept pattern, such as heartbeats, don't replace it.
+type StartupCoalescer struct {
+	underlying Notifier
+	window     time.Duration
+	keep       map[string]bool
+
+	mu      sync.Mutex
+	pending *time.Timer
+}
+
+// NewStartupCoalescer creates a new startup coalescer. Notifications with the keep
+// patterns are sent without dropping a pending startup notification.
+func NewStartupCoalescer(underlying Notifier, window time.Duration, keep ...string) *StartupCoalescer {
+	sc := &StartupCoalescer{
+		underlying: underlying,
+		window:     window,
+		keep:       make(map[string]bool, len(keep)),
+	}
+	for _, pattern := range keep {
+		sc.keep[pattern] = true
+	}
+	return sc
+}
+
+// SendStartup schedules the startup notification to be sent after the window
+func (sc *StartupCoalescer) SendStartup(notification Notification) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if sc.pending != nil {
+		sc.pending.Stop()
+	}
+	sc.pending = time.AfterFunc(sc.window, func() {
+		sc.mu.Lock()
+		sc.pending = nil
+		sc.mu.Unlock()
+
+		_ = sc.underlying.Send(notification)
+	})
+}
+
+// Send implements the Notifier interface, cancelling any pending startup notification
+// unless the notification's pattern is kept
+func (sc *StartupCoalescer) Send(notification Notification) error {
+	sc.mu.Lock()
+	if sc.pending != nil && !sc.keep[notification.Pattern] {
+		sc.pending.Stop()
+		sc.pending = nil
+	}
+	sc.mu.Unlock()
+
+	return sc.underlying.Send(notification)
+}
+
+// Close drops any pending startup notification
+func (sc *StartupCoalescer) Close() error {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if sc.pending != nil {
+		sc.pending.Stop()
+		sc.pending = nil
+	}
+
+	return nil
+}
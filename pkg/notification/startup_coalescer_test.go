@@ -0,0 +1,111 @@
+package notification
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStartupCoalescer_EarlyNotificationSuppressesStartup(t *testing.T) {
+	mock := &testNotifier{}
+	sc := NewStartupCoalescer(mock, 50*time.Millisecond)
+	defer func() { _ = sc.Close() }()
+
+	sc.SendStartup(Notification{Title: "Claude Code Session Started", Pattern: "startup"})
+
+	// A real notification arrives within the window
+	time.Sleep(10 * time.Millisecond)
+	if err := sc.Send(Notification{Title: "Claude needs attention", Pattern: "question"}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	time.Sleep(80 * time.Millisecond)
+
+	notifications := mock.getNotifications()
+	if len(notifications) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(notifications))
+	}
+	if notifications[0].Pattern != "question" {
+		t.Errorf("expected only the question notification, got %q", notifications[0].Pattern)
+	}
+}
+
+func TestStartupCoalescer_KeptPatternsLeaveStartup(t *testing.T) {
+	tests := []struct {
+		name        string
+		pattern     string
+		wantStartup bool
+	}{
+		{name: "heartbeat", pattern: "heartbeat", wantStartup: true},
+		{name: "backstop", pattern: "backstop", wantStartup: true},
+		{name: "output pattern", pattern: "question", wantStartup: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &testNotifier{}
+			sc := NewStartupCoalescer(mock, 30*time.Millisecond, "heartbeat", "backstop")
+			defer func() { _ = sc.Close() }()
+
+			sc.SendStartup(Notification{Pattern: "startup"})
+			if err := sc.Send(Notification{Pattern: tt.pattern}); err != nil {
+				t.Fatalf("Send failed: %v", err)
+			}
+
+			time.Sleep(70 * time.Millisecond)
+
+			gotStartup := false
+			for _, n := range mock.getNotifications() {
+				if n.Pattern == "startup" {
+					gotStartup = true
+				}
+			}
+			if gotStartup != tt.wantStartup {
+				t.Errorf("startup sent = %v, want %v", gotStartup, tt.wantStartup)
+			}
+		})
+	}
+}
+
+func TestStartupCoalescer_StartupSentWithoutEarlyNotification(t *testing.T) {
+	mock := &testNotifier{}
+	sc := NewStartupCoalescer(mock, 30*time.Millisecond)
+	defer func() { _ = sc.Close() }()
+
+	sc.SendStartup(Notification{Title: "Claude Code Session Started", Pattern: "startup"})
+
+	if len(mock.getNotifications()) != 0 {
+		t.Error("startup notification should be held back during the window")
+	}
+
+	time.Sleep(70 * time.Millisecond)
+
+	notifications := mock.getNotifications()
+	if len(notifications) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(notifications))
+	}
+	if notifications[0].Pattern != "startup" {
+		t.Errorf("expected startup notification, got %q", notifications[0].Pattern)
+	}
+
+	// Later notifications pass straight through
+	if err := sc.Send(Notification{Pattern: "backstop"}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if len(mock.getNotifications()) != 2 {
+		t.Errorf("expected 2 notifications, got %d", len(mock.getNotifications()))
+	}
+}
+
+func TestStartupCoalescer_CloseDropsPending(t *testing.T) {
+	mock := &testNotifier{}
+	sc := NewStartupCoalescer(mock, 20*time.Millisecond)
+
+	sc.SendStartup(Notification{Pattern: "startup"})
+	_ = sc.Close()
+
+	time.Sleep(50 * time.Millisecond)
+
+	if len(mock.getNotifications()) != 0 {
+		t.Error("expected pending startup notification to be dropped on close")
+	}
+}
@@ -0,0 +1,58 @@
+package notification
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// TmuxRunner runs tmux with the given arguments and returns its output
+type TmuxRunner func(args ...string) ([]byte, error)
+
+// runTmux runs the tmux binary
+func runTmux(args ...string) ([]byte, error) {
+	return exec.Command("tmux", args...).Output()
+}
+
+// TmuxPaneNotifier wraps another notifier and drops notifications while Claude's tmux pane
+// is the one being looked at: the active pane of the active window in an attached session.
+type TmuxPaneNotifier struct {
+	underlying Notifier
+	pane       string
+	run        TmuxRunner
+}
+
+// NewTmuxPaneNotifier creates a new tmux pane aware notifier for the given pane id (e.g. $TMUX_PANE)
+func NewTmuxPaneNotifier(underlying Notifier, pane string) *TmuxPaneNotifier {
+	return &TmuxPaneNotifier{
+		underlying: underlying,
+		pane:       pane,
+		run:        runTmux,
+	}
+}
+
+// Send implements the Notifier interface
+func (tn *TmuxPaneNotifier) Send(notification Notification) error {
+	if tn.paneVisible() {
+		return nil
+	}
+
+	return tn.underlying.Send(notification)
+}
+
+// paneVisible reports whether our pane is currently in front of the user.
+// If tmux can't be queried we assume it isn't, so notifications still go out.
+func (tn *TmuxPaneNotifier) paneVisible() bool {
+	output, err := tn.run("display-message", "-p", "-t", tn.pane,
+		"#{pane_active} #{window_active} #{session_attached}")
+	if err != nil {
+		return false
+	}
+
+	fields := strings.Fields(string(output))
+	if len(fields) != 3 {
+		return false
+	}
+
+	// session_attached is the number of attached clients
+	return fields[0] == "1" && fields[1] == "1" && fields[2] != "0"
+}
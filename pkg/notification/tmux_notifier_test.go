@@ -0,0 +1,73 @@
+package notification
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTmuxPaneNotifier(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		runErr   error
+		wantSent bool
+	}{
+		{
+			name:     "our pane is active",
+			output:   "1 1 1\n",
+			wantSent: false,
+		},
+		{
+			name:     "another pane is active",
+			output:   "0 1 1\n",
+			wantSent: true,
+		},
+		{
+			name:     "our window is in the background",
+			output:   "1 0 1\n",
+			wantSent: true,
+		},
+		{
+			name:     "session detached",
+			output:   "1 1 0\n",
+			wantSent: true,
+		},
+		{
+			name:     "tmux unavailable",
+			runErr:   errors.New("executable file not found"),
+			wantSent: true,
+		},
+		{
+			name:     "unexpected output",
+			output:   "no server running\n",
+			wantSent: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &testNotifier{}
+			tn := NewTmuxPaneNotifier(mock, "%3")
+
+			var gotArgs []string
+			tn.run = func(args ...string) ([]byte, error) {
+				gotArgs = args
+				return []byte(tt.output), tt.runErr
+			}
+
+			if err := tn.Send(Notification{Title: "Test"}); err != nil {
+				t.Fatalf("Send failed: %v", err)
+			}
+
+			if got := strings.Join(gotArgs, " "); !strings.Contains(got, "-t %3") {
+				t.Errorf("tmux args = %q, want target pane %%3", got)
+			}
+
+			sent := len(mock.getNotifications()) == 1
+			if sent != tt.wantSent {
+				t.Errorf("sent = %v, want %v", sent, tt.wantSent)
+			}
+		})
+	}
+}
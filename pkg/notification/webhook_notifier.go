@@ -0,0 +1,124 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"text/template"
+	"time"
+)
+
+// webhookFuncs are the functions available to webhook body templates
+var webhookFuncs = template.FuncMap{
+	"json": quoteJSON,
+}
+
+// quoteJSON quotes s as a JSON string for use inside a JSON body, e.g. {"text": {{json .Message}}}
+func quoteJSON(s string) string {
+	// Marshaling a string can't fail
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+// webhookPayload is the default webhook body when no template is configured
+type webhookPayload struct {
+	Title   string    `json:"title"`
+	Message string    `json:"message"`
+	Pattern string    `json:"pattern"`
+	Time    time.Time `json:"time"`
+}
+
+// ParseWebhookTemplate parses a webhook body template rendered with the Notification fields
+func ParseWebhookTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("webhook_body_template").Funcs(webhookFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook_body_template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// WebhookNotifier sends notifications to an arbitrary HTTP endpoint
+type WebhookNotifier struct {
+	url        string
+	method     string
+	headers    map[string]string
+	body       *template.Template
+	httpClient *http.Client
+}
+
+// NewWebhookNotifier creates a new webhook notifier. Without a body template the
+// notification is sent as JSON; method defaults to POST.
+func NewWebhookNotifier(url, method string, headers map[string]string, bodyTemplate string) (*WebhookNotifier, error) {
+	var body *template.Template
+	if bodyTemplate != "" {
+		var err error
+		if body, err = ParseWebhookTemplate(bodyTemplate); err != nil {
+			return nil, err
+		}
+	}
+	if method == "" {
+		method = http.MethodPost
+	}
+	return &WebhookNotifier{
+		url:     url,
+		method:  method,
+		headers: headers,
+		body:    body,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}, nil
+}
+
+// Send implements the Notifier interface
+func (wn *WebhookNotifier) Send(notification Notification) error {
+	body, err := wn.render(notification)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(wn.method, wn.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range wn.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := wn.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// render builds the request body for a notification
+func (wn *WebhookNotifier) render(notification Notification) ([]byte, error) {
+	if wn.body == nil {
+		data, err := json.Marshal(webhookPayload{
+			Title:   notification.Title,
+			Message: notification.Message,
+			Pattern: notification.Pattern,
+			Time:    notification.Time,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal notification: %w", err)
+		}
+		return data, nil
+	}
+
+	var b bytes.Buffer
+	if err := wn.body.Execute(&b, notification); err != nil {
+		return nil, fmt.Errorf("failed to render webhook body: %w", err)
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,133 @@
+package notification
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebhookNotifier_Send(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{
+			name:     "template with json quoting",
+			template: `{"text": {{json .Message}}, "source": "{{.Pattern}}"}`,
+			want:     `{"text": "said \"done\"", "source": "backstop"}`,
+		},
+		{
+			name:     "plain text template",
+			template: `{{.Title}}: {{.Message}}`,
+			want:     `Claude Code: api: said "done"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotBody string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				gotBody = string(body)
+			}))
+			defer server.Close()
+
+			wn, err := NewWebhookNotifier(server.URL, "", nil, tt.template)
+			if err != nil {
+				t.Fatalf("NewWebhookNotifier() error = %v", err)
+			}
+
+			err = wn.Send(Notification{Title: "Claude Code: api", Message: `said "done"`, Pattern: "backstop"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if gotBody != tt.want {
+				t.Errorf("body = %q, want %q", gotBody, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookNotifier_DefaultBody(t *testing.T) {
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+	}))
+	defer server.Close()
+
+	wn, err := NewWebhookNotifier(server.URL, "", nil, "")
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier() error = %v", err)
+	}
+
+	err = wn.Send(Notification{Title: "Test", Message: "Hello", Pattern: "startup", Time: time.Now()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if payload["title"] != "Test" || payload["message"] != "Hello" || payload["pattern"] != "startup" {
+		t.Errorf("unexpected payload: %v", payload)
+	}
+}
+
+func TestWebhookNotifier_MethodAndHeaders(t *testing.T) {
+	var gotMethod string
+	var gotHeader http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotHeader = r.Header
+	}))
+	defer server.Close()
+
+	headers := map[string]string{
+		"Authorization": "Bearer token",
+		"Content-Type":  "text/plain",
+	}
+	wn, err := NewWebhookNotifier(server.URL, http.MethodPut, headers, "")
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier() error = %v", err)
+	}
+	if err := wn.Send(Notification{Title: "Test"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotMethod != http.MethodPut {
+		t.Errorf("method = %s, want PUT", gotMethod)
+	}
+	if got := gotHeader.Get("Authorization"); got != "Bearer token" {
+		t.Errorf("Authorization = %q, want %q", got, "Bearer token")
+	}
+	if got := gotHeader.Get("Content-Type"); got != "text/plain" {
+		t.Errorf("Content-Type = %q, want configured header to override the default", got)
+	}
+}
+
+func TestWebhookNotifier_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	wn, err := NewWebhookNotifier(server.URL, "", nil, "")
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier() error = %v", err)
+	}
+
+	err = wn.Send(Notification{Title: "Test"})
+	if err == nil || !strings.Contains(err.Error(), "status 400") {
+		t.Errorf("expected status error, got %v", err)
+	}
+}
+
+func TestNewWebhookNotifier_InvalidTemplate(t *testing.T) {
+	_, err := NewWebhookNotifier("https://hooks.example.com", "", nil, `{"text": {{.Message}`)
+	if err == nil || !strings.Contains(err.Error(), "invalid webhook_body_template") {
+		t.Errorf("expected invalid template error, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package process
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+)
+
+// ExitStats describes the resources the process used over its run
+type ExitStats struct {
+	// Duration is the wall-clock time from start to exit
+	Duration time.Duration
+	// UserTime and SystemTime are the CPU time used, when known
+	UserTime   time.Duration
+	SystemTime time.Duration
+	HasCPU     bool
+}
+
+// String formats the stats, e.g. "ran 1h2m3s, CPU 12.34s user, 1.2s system"
+func (s ExitStats) String() string {
+	stats := fmt.Sprintf("ran %s", s.Duration.Round(time.Second))
+	if s.HasCPU {
+		stats += fmt.Sprintf(", CPU %s user, %s system",
+			s.UserTime.Round(10*time.Millisecond), s.SystemTime.Round(10*time.Millisecond))
+	}
+	return stats
+}
+
+// cpuTimes is the part of os.ProcessState that reports CPU usage
+type cpuTimes interface {
+	UserTime() time.Duration
+	SystemTime() time.Duration
+}
+
+// newExitStats builds the stats for a run of the given duration, with CPU times if known
+func newExitStats(duration time.Duration, times cpuTimes) ExitStats {
+	stats := ExitStats{Duration: duration}
+	if times != nil {
+		stats.UserTime = times.UserTime()
+		stats.SystemTime = times.SystemTime()
+		stats.HasCPU = true
+	}
+	return stats
+}
+
+// processCPUTimes returns the CPU times of an exited process, or nil if they weren't recorded
+func processCPUTimes(state *os.ProcessState) cpuTimes {
+	if state == nil {
+		return nil
+	}
+	// The times come from the rusage, which is missing unless the process was waited for
+	if usage, ok := state.SysUsage().(*syscall.Rusage); !ok || usage == nil {
+		return nil
+	}
+	return state
+}
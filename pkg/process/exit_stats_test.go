@@ -0,0 +1,53 @@
+package process
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// fakeCPUTimes reports fixed CPU times
+type fakeCPUTimes struct {
+	user, system time.Duration
+}
+
+func (f fakeCPUTimes) UserTime() time.Duration   { return f.user }
+func (f fakeCPUTimes) SystemTime() time.Duration { return f.system }
+
+func TestExitStats(t *testing.T) {
+	tests := []struct {
+		name     string
+		duration time.Duration
+		times    cpuTimes
+		want     string
+	}{
+		{
+			name:     "with CPU times",
+			duration: time.Hour + 2*time.Minute + 3*time.Second + 400*time.Millisecond,
+			times:    fakeCPUTimes{user: 12345 * time.Millisecond, system: 1200 * time.Millisecond},
+			want:     "ran 1h2m3s, CPU 12.35s user, 1.2s system",
+		},
+		{
+			name:     "without CPU times",
+			duration: 90 * time.Second,
+			want:     "ran 1m30s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newExitStats(tt.duration, tt.times).String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessCPUTimes(t *testing.T) {
+	if processCPUTimes(nil) != nil {
+		t.Error("expected no CPU times without a process state")
+	}
+	if processCPUTimes(&os.ProcessState{}) != nil {
+		t.Error("expected no CPU times without rusage")
+	}
+}
@@ -13,5 +13,6 @@ type PTY interface {
 	ProcessState() *os.ProcessState
 	Process() *os.Process
 	GetPTY() *os.File
-	CopyIO(stdin io.Reader, stdout, stderr io.Writer, outputHandler func([]byte), inputHandler func()) error
+	CopyIO(stdin io.Reader, stdout, stderr io.Writer, outputHandler func([]byte), inputHandler func([]byte)) error
+	SetOutputLog(w io.Writer)
 }
@@ -1,12 +1,15 @@
 package process
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/signal"
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/Veraticus/claude-code-ntfy/pkg/config"
 	"github.com/Veraticus/claude-code-ntfy/pkg/interfaces"
@@ -17,15 +20,18 @@ type Manager struct {
 	config        *config.Config
 	ptyManager    PTY
 	outputHandler interfaces.DataHandler
-	inputHandler  func()
+	inputHandler  func([]byte)
+	errorHandler  func(error)
 	exitCode      int
+	startTime     time.Time
+	exitStats     ExitStats
 	mu            sync.Mutex
 	sigChan       chan os.Signal
 	done          chan struct{}
 }
 
 // NewManager creates a new process manager
-func NewManager(cfg *config.Config, outputHandler interfaces.DataHandler, inputHandler func()) *Manager {
+func NewManager(cfg *config.Config, outputHandler interfaces.DataHandler, inputHandler func([]byte)) *Manager {
 	return &Manager{
 		config:        cfg,
 		ptyManager:    NewPTYManager(),
@@ -35,14 +41,42 @@ func NewManager(cfg *config.Config, outputHandler interfaces.DataHandler, inputH
 	}
 }
 
+// SetErrorHandler sets a handler that is also given internal errors, such as I/O errors
+func (m *Manager) SetErrorHandler(handler func(error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.errorHandler = handler
+}
+
+// reportError passes err to the error handler, if one is set
+func (m *Manager) reportError(err error) {
+	m.mu.Lock()
+	handler := m.errorHandler
+	m.mu.Unlock()
+
+	if handler != nil {
+		handler(err)
+	}
+}
+
+// IsNested reports whether we are running inside another claude-code-ntfy session
+func IsNested() bool {
+	return os.Getenv("CLAUDE_CODE_NTFY_WRAPPED") == "1"
+}
+
 // Start starts the Claude Code process
 func (m *Manager) Start(command string, args []string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	// Check for self-wrap
-	if os.Getenv("CLAUDE_CODE_NTFY_WRAPPED") == "1" {
-		return fmt.Errorf("already wrapped by claude-code-ntfy")
+	if IsNested() {
+		if !m.config.AllowNested {
+			return fmt.Errorf("already wrapped by claude-code-ntfy")
+		}
+		// Pass straight through to Claude without monitoring its output
+		slog.Warn("already wrapped, running claude without notifications")
+		m.outputHandler = nil
 	}
 
 	// Set environment to prevent self-wrap
@@ -56,10 +90,26 @@ func (m *Manager) Start(command string, args []string) error {
 	}
 	env = append(env, "CLAUDE_CODE_NTFY_WRAPPED=1")
 
+	// Keep a copy of everything Claude outputs for debugging notifications
+	if m.config.SessionLog != "" {
+		logFile, err := os.OpenFile(m.config.SessionLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open session log: %w", err)
+		}
+		m.ptyManager.SetOutputLog(logFile)
+	}
+
 	// Start the process with PTY
 	if err := m.ptyManager.Start(command, args, env); err != nil {
+		_ = m.ptyManager.Stop()
 		return fmt.Errorf("failed to start process: %w", err)
 	}
+	m.startTime = time.Now()
+
+	// Let the output handler time Claude from here, not from our own setup
+	if starter, ok := m.outputHandler.(interface{ HandleStart() }); ok {
+		starter.HandleStart()
+	}
 
 	// Start I/O copying with output handling
 	go func() {
@@ -70,7 +120,13 @@ func (m *Manager) Start(command string, args []string) error {
 			}
 		}
 		if err := m.ptyManager.CopyIO(os.Stdin, os.Stdout, os.Stderr, handler, m.inputHandler); err != nil {
-			fmt.Fprintf(os.Stderr, "claude-code-ntfy: I/O error: %v\n", err)
+			// Reading the PTY fails like this whenever Claude exits, which is nothing to report
+			if errors.Is(err, syscall.EIO) || errors.Is(err, os.ErrClosed) {
+				slog.Debug("I/O stopped", "err", err)
+			} else {
+				slog.Warn("I/O error", "err", err)
+				m.reportError(fmt.Errorf("I/O error: %w", err))
+			}
 		}
 	}()
 
@@ -89,8 +145,9 @@ func (m *Manager) Wait() error {
 	err := m.ptyManager.Wait()
 
 	m.mu.Lock()
-	if m.ptyManager.ProcessState() != nil {
-		m.exitCode = m.ptyManager.ProcessState().ExitCode()
+	if state := m.ptyManager.ProcessState(); state != nil {
+		m.exitCode = state.ExitCode()
+		m.exitStats = newExitStats(time.Since(m.startTime), processCPUTimes(state))
 	}
 	m.mu.Unlock()
 
@@ -113,6 +170,13 @@ func (m *Manager) ExitCode() int {
 	return m.exitCode
 }
 
+// ExitStats returns how long the process ran and the CPU time it used
+func (m *Manager) ExitStats() ExitStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.exitStats
+}
+
 // setupSignalForwarding sets up signal forwarding to the child process
 func (m *Manager) setupSignalForwarding() {
 	m.sigChan = make(chan os.Signal, 1)
@@ -139,7 +203,7 @@ func (m *Manager) forwardSignals() {
 				if err := m.ptyManager.Process().Signal(sig); err != nil {
 					// Process might have already exited, but log it
 					if err != os.ErrProcessDone {
-						fmt.Fprintf(os.Stderr, "claude-code-ntfy: signal forward error: %v\n", err)
+						slog.Warn("failed to forward signal", "signal", sig, "err", err)
 					}
 				}
 			}
@@ -179,3 +243,25 @@ func (m *Manager) Stop() error {
 
 	return nil
 }
+
+// StopWithGrace stops the process like Stop, then kills it if it still
+// hasn't exited once grace has passed
+func (m *Manager) StopWithGrace(grace time.Duration) error {
+	if err := m.Stop(); err != nil {
+		return err
+	}
+
+	go func() {
+		select {
+		case <-m.done:
+		case <-time.After(grace):
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			if m.ptyManager != nil && m.ptyManager.Process() != nil {
+				_ = m.ptyManager.Process().Kill()
+			}
+		}
+	}()
+
+	return nil
+}
@@ -3,8 +3,11 @@ package process
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"io"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"syscall"
 	"testing"
 	"time"
@@ -23,6 +26,8 @@ type MockPTYManager struct {
 	processState *os.ProcessState
 	pty          *os.File
 	ioFunc       func()
+	ioError      error
+	outputLog    io.Writer
 }
 
 func (m *MockPTYManager) Start(command string, args []string, env []string) error {
@@ -62,11 +67,15 @@ func (m *MockPTYManager) GetPTY() *os.File {
 	return nil
 }
 
-func (m *MockPTYManager) CopyIO(stdin io.Reader, stdout, stderr io.Writer, outputHandler func([]byte), inputHandler func()) error {
+func (m *MockPTYManager) CopyIO(stdin io.Reader, stdout, stderr io.Writer, outputHandler func([]byte), inputHandler func([]byte)) error {
 	if m.ioFunc != nil {
 		m.ioFunc()
 	}
-	return nil
+	return m.ioError
+}
+
+func (m *MockPTYManager) SetOutputLog(w io.Writer) {
+	m.outputLog = w
 }
 
 func (m *MockPTYManager) Stop() error {
@@ -82,13 +91,27 @@ func (m *MockOutputHandler) HandleLine(line string) {
 	m.lines = append(m.lines, line)
 }
 
+// MockDataHandler is a mock implementation of DataHandler
+type MockDataHandler struct {
+	MockOutputHandler
+	started bool
+}
+
+func (m *MockDataHandler) HandleData(data []byte) {}
+
+func (m *MockDataHandler) HandleStart() {
+	m.started = true
+}
+
 func TestManager_Start(t *testing.T) {
 	tests := []struct {
-		name       string
-		envWrapped string
-		startError error
-		wantError  bool
-		errorMsg   string
+		name            string
+		envWrapped      string
+		allowNested     bool
+		startError      error
+		wantError       bool
+		errorMsg        string
+		wantPassthrough bool
 	}{
 		{
 			name:       "successful start",
@@ -103,6 +126,19 @@ func TestManager_Start(t *testing.T) {
 			wantError:  true,
 			errorMsg:   "already wrapped",
 		},
+		{
+			name:            "already wrapped with allow nested",
+			envWrapped:      "1",
+			allowNested:     true,
+			wantError:       false,
+			wantPassthrough: true,
+		},
+		{
+			name:        "allow nested without wrapper keeps monitoring",
+			envWrapped:  "",
+			allowNested: true,
+			wantError:   false,
+		},
 		{
 			name:       "start error",
 			envWrapped: "",
@@ -131,14 +167,16 @@ func TestManager_Start(t *testing.T) {
 			}
 
 			cfg := config.DefaultConfig()
+			cfg.AllowNested = tt.allowNested
 			mockPTY := &MockPTYManager{
 				startError: tt.startError,
 			}
 
+			handler := &MockDataHandler{}
 			manager := &Manager{
 				config:        cfg,
 				ptyManager:    mockPTY,
-				outputHandler: nil,
+				outputHandler: handler,
 				done:          make(chan struct{}),
 			}
 
@@ -157,11 +195,79 @@ func TestManager_Start(t *testing.T) {
 				if !mockPTY.started {
 					t.Error("PTY manager was not started")
 				}
+				if tt.wantPassthrough && manager.outputHandler != nil {
+					t.Error("expected nested pass-through to drop the output handler")
+				}
+				if !tt.wantPassthrough && manager.outputHandler == nil {
+					t.Error("expected output handler to be kept")
+				}
+				if handler.started == tt.wantPassthrough {
+					t.Errorf("output handler started = %v, want %v", handler.started, !tt.wantPassthrough)
+				}
 			}
 		})
 	}
 }
 
+func TestManager_SessionLog(t *testing.T) {
+	t.Setenv("CLAUDE_CODE_NTFY_WRAPPED", "")
+
+	path := filepath.Join(t.TempDir(), "session.log")
+	if err := os.WriteFile(path, []byte("earlier session\n"), 0600); err != nil {
+		t.Fatalf("failed to write log: %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.SessionLog = path
+	mockPTY := &MockPTYManager{}
+	manager := &Manager{
+		config:     cfg,
+		ptyManager: mockPTY,
+		done:       make(chan struct{}),
+	}
+
+	if err := manager.Start("test", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logFile, ok := mockPTY.outputLog.(*os.File)
+	if !ok {
+		t.Fatalf("expected the session log file to be passed to the PTY, got %T", mockPTY.outputLog)
+	}
+	defer func() { _ = logFile.Close() }()
+
+	if _, err := logFile.Write([]byte("this session\n")); err != nil {
+		t.Fatalf("failed to write log: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log: %v", err)
+	}
+	if string(data) != "earlier session\nthis session\n" {
+		t.Errorf("expected the log to be appended to, got %q", data)
+	}
+}
+
+func TestManager_SessionLogError(t *testing.T) {
+	t.Setenv("CLAUDE_CODE_NTFY_WRAPPED", "")
+
+	cfg := config.DefaultConfig()
+	cfg.SessionLog = filepath.Join(t.TempDir(), "missing", "session.log")
+	mockPTY := &MockPTYManager{}
+	manager := &Manager{
+		config:     cfg,
+		ptyManager: mockPTY,
+		done:       make(chan struct{}),
+	}
+
+	err := manager.Start("test", nil)
+	if err == nil || !contains(err.Error(), "failed to open session log") {
+		t.Errorf("expected session log error, got %v", err)
+	}
+	if mockPTY.started {
+		t.Error("expected Claude not to be started")
+	}
+}
+
 func TestManager_Wait(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -309,7 +415,120 @@ func TestManager_Stop(t *testing.T) {
 	}
 }
 
+func TestManager_StopWithGrace(t *testing.T) {
+	// A process that ignores SIGTERM can only be stopped by the kill after the grace period
+	cmd := exec.Command("sh", "-c", "trap '' TERM; sleep 30")
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start process: %v", err)
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	manager := &Manager{
+		config:     config.DefaultConfig(),
+		ptyManager: &MockPTYManager{process: cmd.Process},
+		done:       make(chan struct{}),
+	}
+
+	// Give the shell time to install its trap
+	time.Sleep(100 * time.Millisecond)
+
+	if err := manager.StopWithGrace(100 * time.Millisecond); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case <-exited:
+		status := cmd.ProcessState.Sys().(syscall.WaitStatus)
+		if !status.Signaled() || status.Signal() != syscall.SIGKILL {
+			t.Errorf("expected the process to be killed, got %v", cmd.ProcessState)
+		}
+	case <-time.After(5 * time.Second):
+		_ = cmd.Process.Kill()
+		t.Fatal("process was not killed after the grace period")
+	}
+}
+
 // Helper function to check if a string contains a substring
 func contains(s, substr string) bool {
 	return bytes.Contains([]byte(s), []byte(substr))
 }
+
+func TestManager_ExitStats(t *testing.T) {
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("failed to run process: %v", err)
+	}
+
+	manager := &Manager{
+		config:     config.DefaultConfig(),
+		ptyManager: &MockPTYManager{processState: cmd.ProcessState},
+		startTime:  time.Now().Add(-2 * time.Second),
+		done:       make(chan struct{}),
+	}
+
+	if err := manager.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stats := manager.ExitStats()
+	if stats.Duration < 2*time.Second || stats.Duration > time.Minute {
+		t.Errorf("expected a duration of about 2s, got %v", stats.Duration)
+	}
+	if !stats.HasCPU {
+		t.Error("expected CPU times from the process state")
+	}
+}
+
+func TestManager_ErrorHandler(t *testing.T) {
+	t.Setenv("CLAUDE_CODE_NTFY_WRAPPED", "")
+
+	tests := []struct {
+		name       string
+		ioError    error
+		wantReport string
+	}{
+		{
+			name:       "I/O error",
+			ioError:    fmt.Errorf("stdin copy error: %w", &os.PathError{Op: "write", Path: "/dev/ptmx", Err: syscall.ENOSPC}),
+			wantReport: "I/O error: stdin copy error: write /dev/ptmx: no space left on device",
+		},
+		{
+			name:    "PTY closed as Claude exits",
+			ioError: fmt.Errorf("stdout copy error: %w", &os.PathError{Op: "read", Path: "/dev/ptmx", Err: syscall.EIO}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			copied := make(chan struct{})
+			manager := &Manager{
+				config: config.DefaultConfig(),
+				ptyManager: &MockPTYManager{
+					ioError: tt.ioError,
+					ioFunc:  func() { close(copied) },
+				},
+				done: make(chan struct{}),
+			}
+			errs := make(chan error, 1)
+			manager.SetErrorHandler(func(err error) { errs <- err })
+
+			if err := manager.Start("test", nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer manager.cleanupSignals()
+			<-copied
+
+			select {
+			case err := <-errs:
+				if err.Error() != tt.wantReport {
+					t.Errorf("reported %q, want %q", err, tt.wantReport)
+				}
+			case <-time.After(200 * time.Millisecond):
+				if tt.wantReport != "" {
+					t.Fatal("expected the I/O error to be reported")
+				}
+			}
+		})
+	}
+}
@@ -1,8 +1,10 @@
 package process
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -20,6 +22,7 @@ type PTYManager struct {
 	stopChan    chan struct{}
 	wg          sync.WaitGroup
 	restoreFunc func()
+	outputLog   io.Writer
 }
 
 // Ensure PTYManager implements PTY
@@ -53,9 +56,9 @@ func (p *PTYManager) Start(command string, args []string, env []string) error {
 	}
 
 	// Copy terminal size
-	if err := p.copyTerminalSize(); err != nil {
-		// Log but don't fail - some environments don't have a terminal
-		fmt.Fprintf(os.Stderr, "claude-code-ntfy: failed to copy terminal size: %v\n", err)
+	if err := p.copyTerminalSize(); err != nil && !errors.Is(err, syscall.ENOTTY) {
+		// Log but don't fail - piped stdin has no size to copy, which needs no warning
+		slog.Warn("failed to copy terminal size", "err", err)
 	}
 
 	// Start monitoring for terminal size changes
@@ -123,9 +126,33 @@ func (p *PTYManager) Stop() error {
 		p.restoreFunc = nil
 	}
 
+	// Close the output log, so later output isn't written to it
+	if closer, ok := p.outputLog.(io.Closer); ok {
+		_ = closer.Close()
+	}
+	p.outputLog = nil
+
 	return nil
 }
 
+// SetOutputLog makes CopyIO write a copy of everything the process outputs to w.
+// Stop closes w if it is an io.Closer.
+func (p *PTYManager) SetOutputLog(w io.Writer) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.outputLog = w
+}
+
+// logOutput writes data to the output log, if there is one.
+// Logging is best effort and never interrupts the session.
+func (p *PTYManager) logOutput(data []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.outputLog != nil {
+		_, _ = p.outputLog.Write(data)
+	}
+}
+
 // copyTerminalSize copies the terminal size from stdin to the PTY
 func (p *PTYManager) copyTerminalSize() error {
 	size, err := pty.GetsizeFull(os.Stdin)
@@ -151,7 +178,7 @@ func (p *PTYManager) monitorTerminalSize() {
 			p.mu.Lock()
 			if p.pty != nil {
 				if err := p.copyTerminalSize(); err != nil {
-					fmt.Fprintf(os.Stderr, "claude-code-ntfy: failed to resize PTY: %v\n", err)
+					slog.Warn("failed to resize PTY", "err", err)
 				}
 			}
 			p.mu.Unlock()
@@ -162,7 +189,7 @@ func (p *PTYManager) monitorTerminalSize() {
 }
 
 // CopyIO handles copying between PTY and standard streams
-func (p *PTYManager) CopyIO(stdin io.Reader, stdout, stderr io.Writer, outputHandler func([]byte), inputHandler func()) error {
+func (p *PTYManager) CopyIO(stdin io.Reader, stdout, stderr io.Writer, outputHandler func([]byte), inputHandler func([]byte)) error {
 	p.mu.Lock()
 	if p.pty == nil {
 		p.mu.Unlock()
@@ -170,7 +197,8 @@ func (p *PTYManager) CopyIO(stdin io.Reader, stdout, stderr io.Writer, outputHan
 	}
 	p.mu.Unlock()
 
-	// Store the restore function so we can call it from Stop()
+	// Store the restore function so we can call it from Stop().
+	// Piped stdin is not a terminal, so raw mode fails and it is copied as is.
 	if file, ok := stdin.(*os.File); ok {
 		if restore, err := setRawMode(int(file.Fd())); err == nil {
 			p.mu.Lock()
@@ -193,24 +221,16 @@ func (p *PTYManager) CopyIO(stdin io.Reader, stdout, stderr io.Writer, outputHan
 	// Error channel to capture any errors
 	errChan := make(chan error, 2)
 
-	// Copy from stdin to PTY
+	// Copy from stdin to PTY. A PTY has no write side to close on its own,
+	// so the end of piped input is not passed on: Claude keeps the session
+	// open until it exits by itself or is stopped.
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		if inputHandler != nil {
-			// Use an inputReader to detect stdin activity
-			reader := &inputReader{
-				reader:  stdin,
-				handler: inputHandler,
-			}
-			if _, err := io.Copy(p.pty, reader); err != nil {
-				errChan <- fmt.Errorf("stdin copy error: %w", err)
-			}
-		} else {
-			// Direct copy without handling
-			if _, err := io.Copy(p.pty, stdin); err != nil {
-				errChan <- fmt.Errorf("stdin copy error: %w", err)
-			}
+		// The inputReader lets the input handler detect stdin activity
+		reader := &inputReader{reader: stdin, handler: inputHandler}
+		if _, err := io.Copy(p.pty, reader); err != nil {
+			errChan <- fmt.Errorf("stdin copy error: %w", err)
 		}
 	}()
 
@@ -219,20 +239,18 @@ func (p *PTYManager) CopyIO(stdin io.Reader, stdout, stderr io.Writer, outputHan
 	go func() {
 		defer wg.Done()
 
-		if outputHandler != nil {
-			// Use a TeeReader to handle output
-			reader := &outputReader{
-				reader:  p.pty,
-				handler: outputHandler,
-			}
-			if _, err := io.Copy(stdout, reader); err != nil {
-				errChan <- fmt.Errorf("stdout copy error: %w", err)
-			}
-		} else {
-			// Direct copy without handling
-			if _, err := io.Copy(stdout, p.pty); err != nil {
-				errChan <- fmt.Errorf("stdout copy error: %w", err)
-			}
+		// Log the raw output stream before the handler sees it
+		reader := &outputReader{
+			reader: p.pty,
+			handler: func(data []byte) {
+				p.logOutput(data)
+				if outputHandler != nil {
+					outputHandler(data)
+				}
+			},
+		}
+		if _, err := io.Copy(stdout, reader); err != nil {
+			errChan <- fmt.Errorf("stdout copy error: %w", err)
 		}
 	}()
 
@@ -262,16 +280,16 @@ func (r *outputReader) Read(p []byte) (n int, err error) {
 	return n, err
 }
 
-// inputReader wraps a reader and calls a handler when input is detected
+// inputReader wraps a reader and calls a handler for each chunk of input
 type inputReader struct {
 	reader  io.Reader
-	handler func()
+	handler func([]byte)
 }
 
 func (r *inputReader) Read(p []byte) (n int, err error) {
 	n, err = r.reader.Read(p)
 	if n > 0 && r.handler != nil {
-		r.handler()
+		r.handler(p[:n])
 	}
 	return n, err
 }
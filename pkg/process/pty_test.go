@@ -271,3 +271,155 @@ func TestOutputReader(t *testing.T) {
 		t.Errorf("handler got %q but expected %q", handlerData[0], testData)
 	}
 }
+
+func TestInputReader(t *testing.T) {
+	var got []byte
+	reader := &inputReader{
+		reader: bytes.NewBufferString("hello\r"),
+		handler: func(data []byte) {
+			got = append(got, data...)
+		},
+	}
+
+	out, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if string(out) != "hello\r" || string(got) != "hello\r" {
+		t.Errorf("read %q, handler saw %q, want both to be %q", out, got, "hello\r")
+	}
+}
+
+func TestPTYManager_CopyIO_NonTTYStdin(t *testing.T) {
+	if runtime.GOOS == "windows" || os.Getenv("CI") == "true" {
+		t.Skip("PTY tests require Unix environment")
+	}
+
+	tests := []struct {
+		name  string
+		stdin func(t *testing.T) io.Reader
+	}{
+		{
+			name: "buffer",
+			stdin: func(t *testing.T) io.Reader {
+				return bytes.NewBufferString("piped input\nno newline")
+			},
+		},
+		{
+			name: "pipe",
+			stdin: func(t *testing.T) io.Reader {
+				r, w, err := os.Pipe()
+				if err != nil {
+					t.Fatalf("failed to create pipe: %v", err)
+				}
+				t.Cleanup(func() { _ = r.Close() })
+				go func() {
+					_, _ = w.WriteString("piped input\nno newline")
+					_ = w.Close()
+				}()
+				return r
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ptyMgr := NewPTYManager()
+			if err := ptyMgr.Start("cat", []string{}, os.Environ()); err != nil {
+				t.Fatalf("failed to start: %v", err)
+			}
+
+			output := &bytes.Buffer{}
+			var handled bytes.Buffer
+			handler := func(data []byte) {
+				handled.Write(data)
+			}
+
+			done := make(chan error, 1)
+			go func() {
+				done <- ptyMgr.CopyIO(tt.stdin(t), output, nil, handler, nil)
+			}()
+
+			// The end of the piped input is not passed on, so cat keeps running
+			// until it is stopped. Give it time to echo the input first.
+			time.Sleep(300 * time.Millisecond)
+			select {
+			case <-done:
+				t.Fatal("copying stopped while cat was still running")
+			default:
+			}
+			_ = ptyMgr.Process().Kill()
+			_ = ptyMgr.Wait()
+			<-done
+
+			got := output.String()
+			if !strings.Contains(got, "piped input") || !strings.Contains(got, "no newline") {
+				t.Errorf("expected piped input in output, got %q", got)
+			}
+			if handled.String() != got {
+				t.Errorf("output handler saw %q, want %q", handled.String(), got)
+			}
+			if strings.Contains(got, "\033[?1004") {
+				t.Errorf("expected no focus reporting sequence, got %q", got)
+			}
+		})
+	}
+}
+
+// closeRecorder is an in-memory output log that records being closed
+type closeRecorder struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestPTYManager_OutputLog(t *testing.T) {
+	if runtime.GOOS == "windows" || os.Getenv("CI") == "true" {
+		t.Skip("PTY tests require Unix environment")
+	}
+
+	ptyMgr := NewPTYManager()
+	outputLog := &closeRecorder{}
+	ptyMgr.SetOutputLog(outputLog)
+	// Stay around briefly so the output is read before Wait closes the PTY
+	if err := ptyMgr.Start("sh", []string{"-c", `printf '\033]0;title\007session output\n'; sleep 0.2`}, os.Environ()); err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+
+	output := &bytes.Buffer{}
+	done := make(chan error, 1)
+	go func() {
+		done <- ptyMgr.CopyIO(bytes.NewReader(nil), output, nil, nil, nil)
+	}()
+
+	_ = ptyMgr.Wait()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("CopyIO did not complete in time")
+	}
+	if err := ptyMgr.Stop(); err != nil {
+		t.Fatalf("failed to stop: %v", err)
+	}
+
+	// The log gets the raw stream, escape sequences included, as well as stdout
+	if got := outputLog.String(); got != output.String() {
+		t.Errorf("expected log %q to match output %q", got, output.String())
+	}
+	if !strings.Contains(outputLog.String(), "\033]0;title\007session output") {
+		t.Errorf("expected raw output in log, got %q", outputLog.String())
+	}
+	if !outputLog.closed {
+		t.Error("expected Stop to close the output log")
+	}
+
+	// Output after Stop is not logged
+	ptyMgr.logOutput([]byte("late"))
+	if strings.Contains(outputLog.String(), "late") {
+		t.Error("expected no logging after Stop")
+	}
+}